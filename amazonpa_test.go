@@ -1,6 +1,8 @@
 package amazonpa
 
 import (
+	"context"
+	"net/url"
 	"testing"
 	"time"
 )
@@ -67,3 +69,101 @@ func TestRequestURLAndSignature(t *testing.T) {
 		t.Error("Request signed URL is wrong")
 	}
 }
+
+func TestPresignURL(t *testing.T) {
+	client := newTestClient()
+	params := map[string]string{"ItemId": "0679722769"}
+
+	presigned, err := client.PresignURL("ItemLookup", params, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestURL, _ := url.Parse(presigned)
+	query := requestURL.Query()
+
+	if query.Get("Timestamp") == "" || query.Get("Expires") != "" {
+		t.Error("Presigned URL without expiration must carry a Timestamp")
+	}
+	if query.Get("ItemId") != "0679722769" || query.Get("Signature") == "" {
+		t.Error("Presigned URL is wrong")
+	}
+
+	expiresAt := time.Date(2100, 1, 1, 12, 0, 0, 0, time.UTC)
+	presigned, err = client.PresignURL("ItemLookup", params, expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestURL, _ = url.Parse(presigned)
+	query = requestURL.Query()
+
+	if query.Get("Expires") != "2100-01-01T12:00:00Z" || query.Get("Timestamp") != "" {
+		t.Error("Presigned URL with expiration must carry Expires only")
+	}
+
+	if _, err := client.PresignURL("ItemLookup", params, time.Now().Add(-time.Minute)); err == nil {
+		t.Error("Presigned URL with past expiration must fail")
+	}
+
+	for _, parameter := range []string{"Expires", "Timestamp"} {
+		if _, err := client.PresignURL("ItemLookup", map[string]string{parameter: "2100-01-01T12:00:00Z"}, time.Time{}); err == nil {
+			t.Errorf("Presigned URL with %s in the parameters must fail", parameter)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := newRateLimiter(50)
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Requests were not spaced out: %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait must stop when the context is done, got %v", err)
+	}
+}
+
+func TestQueryWireFormat(t *testing.T) {
+	query := ItemSearchQuery{
+		SearchIndex:           SearchIndexBooks,
+		Sort:                  SortSalesRank,
+		IncludeReviewsSummary: Bool(false),
+		ItemPage:              2,
+		MinimumPrice:          1500,
+		ResponseGroups:        []ResponseGroup{ResponseGroupLarge, ResponseGroupOffers},
+	}
+
+	parameters := query.Parameters()
+	expected := map[string]string{
+		"SearchIndex":           "Books",
+		"Sort":                  "salesrank",
+		"IncludeReviewsSummary": "False",
+		"ItemPage":              "2",
+		"MinimumPrice":          "1500",
+		"MaximumPrice":          "",
+		"TruncateReviewsAt":     "",
+		"ResponseGroup":         "Large,Offers",
+	}
+
+	for key, value := range expected {
+		if parameters[key] != value {
+			t.Errorf("Parameter %s is %q instead of %q", key, parameters[key], value)
+		}
+	}
+
+	lookup := ItemLookupQuery{IDType: IDTypeEAN, IncludeReviewsSummary: Bool(true), TruncateReviewsAt: Int(0)}.Parameters()
+	assertEqualStr(t, lookup["IdType"], "EAN", "Bad IdType")
+	assertEqualStr(t, lookup["TruncateReviewsAt"], "0", "Bad TruncateReviewsAt")
+	assertEqualStr(t, lookup["IncludeReviewsSummary"], "True", "Bad IncludeReviewsSummary")
+}
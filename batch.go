@@ -0,0 +1,130 @@
+package amazonpa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mattbit/amazonpa/ids"
+)
+
+// MaxItemIDs is the maximum number of item IDs of an ItemLookup request
+const MaxItemIDs = 10
+
+// LookupItems looks up any number of items, splitting the item IDs of the
+// query in ItemLookup requests of at most MaxItemIDs items each
+func (client Client) LookupItems(query ItemLookupQuery) ([]Item, error) {
+	return client.LookupItemsContext(context.Background(), query)
+}
+
+// LookupItemsContext looks up any number of items like LookupItems, giving
+// up when the context is done
+func (client Client) LookupItemsContext(ctx context.Context, query ItemLookupQuery) ([]Item, error) {
+	var items []Item
+
+	ids := query.ItemIDs
+
+	for start := 0; start < len(ids); start += MaxItemIDs {
+		end := start + MaxItemIDs
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		batch := query
+		batch.ItemIDs = ids[start:end]
+
+		err := client.ItemLookupStreamContext(ctx, batch, func(item Item) error {
+			items = append(items, item)
+			return nil
+		})
+
+		if err != nil {
+			return items, err
+		}
+	}
+
+	return items, nil
+}
+
+// HydrateTopItems looks up the full items of a list of top items (such as
+// the ones returned by BrowseNode.TopItems) with the given response groups
+func (client Client) HydrateTopItems(topItems []TopItem, responseGroups []ResponseGroup) ([]Item, error) {
+	return client.HydrateTopItemsContext(context.Background(), topItems, responseGroups)
+}
+
+// HydrateTopItemsContext looks up the full items of a list of top items
+// like HydrateTopItems, giving up when the context is done
+func (client Client) HydrateTopItemsContext(ctx context.Context, topItems []TopItem, responseGroups []ResponseGroup) ([]Item, error) {
+	asins := make([]string, len(topItems))
+	for i, topItem := range topItems {
+		asins[i] = topItem.ASIN
+	}
+
+	return client.LookupItemsContext(ctx, ItemLookupQuery{ItemIDs: asins, ResponseGroups: responseGroups})
+}
+
+// identifierGroups maps the identifier types to the ItemLookup IdType and
+// the default SearchIndex, in the order the groups are looked up
+var identifierGroups = []struct {
+	types       []ids.Type
+	idType      IDType
+	searchIndex SearchIndex
+}{
+	{[]ids.Type{ids.ASIN}, IDTypeASIN, ""},
+	{[]ids.Type{ids.ISBN10, ids.ISBN13}, IDTypeISBN, SearchIndexBooks},
+	{[]ids.Type{ids.EAN, ids.JAN}, IDTypeEAN, SearchIndexAll},
+	{[]ids.Type{ids.UPC}, IDTypeUPC, SearchIndexAll},
+}
+
+// LookupIdentifiers looks up items by a mix of ASINs, ISBNs, EANs, JANs
+// and UPCs. The identifiers are detected and grouped by IdType into
+// batched ItemLookup requests based on query, whose IDType and ItemIDs are
+// replaced. The SearchIndex of the query, if set, is used for the non-ASIN
+// identifiers.
+func (client Client) LookupIdentifiers(identifiers []string, query ItemLookupQuery) ([]Item, error) {
+	return client.LookupIdentifiersContext(context.Background(), identifiers, query)
+}
+
+// LookupIdentifiersContext looks up items by a mix of identifiers like
+// LookupIdentifiers, giving up when the context is done
+func (client Client) LookupIdentifiersContext(ctx context.Context, identifiers []string, query ItemLookupQuery) ([]Item, error) {
+	grouped := map[ids.Type][]string{}
+
+	for _, identifier := range identifiers {
+		idType, id := ids.Detect(identifier)
+		if idType == ids.Unknown {
+			return nil, fmt.Errorf("amazonpa: cannot detect the type of identifier %q", identifier)
+		}
+		grouped[idType] = append(grouped[idType], id)
+	}
+
+	var items []Item
+
+	for _, group := range identifierGroups {
+		batch := query
+		batch.IDType = group.idType
+		batch.ItemIDs = nil
+
+		for _, idType := range group.types {
+			batch.ItemIDs = append(batch.ItemIDs, grouped[idType]...)
+		}
+
+		if len(batch.ItemIDs) == 0 {
+			continue
+		}
+
+		if group.idType == IDTypeASIN {
+			batch.SearchIndex = ""
+		} else if batch.SearchIndex == "" {
+			batch.SearchIndex = group.searchIndex
+		}
+
+		found, err := client.LookupItemsContext(ctx, batch)
+		items = append(items, found...)
+
+		if err != nil {
+			return items, err
+		}
+	}
+
+	return items, nil
+}
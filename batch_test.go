@@ -0,0 +1,60 @@
+package amazonpa
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHydrateTopItems(t *testing.T) {
+	var batches []string
+	client := newFuncClient(func(request *http.Request) (*http.Response, error) {
+		ids := request.URL.Query().Get("ItemId")
+		batches = append(batches, ids)
+
+		return xmlResponse(http.StatusOK, itemLookupXML(strings.Split(ids, ",")...)), nil
+	})
+
+	var topItems []TopItem
+	for i := 0; i < 12; i++ {
+		topItems = append(topItems, TopItem{ASIN: fmt.Sprintf("B%09d", i)})
+	}
+
+	items, err := client.HydrateTopItems(topItems, []ResponseGroup{ResponseGroupMedium})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualInt(t, len(batches), 2, "Bad number of batches")
+	assertEqualInt(t, len(strings.Split(batches[0], ",")), MaxItemIDs, "Bad batch size")
+	assertEqualInt(t, len(items), 12, "Bad number of items")
+	assertEqualStr(t, items[11].ASIN, "B000000011", "Bad item order")
+}
+
+func TestLookupIdentifiers(t *testing.T) {
+	var requests []string
+	client := newFuncClient(func(request *http.Request) (*http.Response, error) {
+		query := request.URL.Query()
+		requests = append(requests, query.Get("IdType")+"/"+query.Get("SearchIndex")+"/"+query.Get("ItemId"))
+
+		return xmlResponse(http.StatusOK, itemLookupXML()), nil
+	})
+
+	_, err := client.LookupIdentifiers([]string{"978-0-306-40615-7", "B003TGG2EA", "036000291452", "0306406152", "4005176874840"}, ItemLookupQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"ASIN//B003TGG2EA",
+		"ISBN/Books/0306406152,9780306406157",
+		"EAN/All/4005176874840",
+		"UPC/All/036000291452",
+	}
+	assertEqualStr(t, strings.Join(requests, " "), strings.Join(expected, " "), "Bad grouped requests")
+
+	if _, err := client.LookupIdentifiers([]string{"B003TGG2EA", "12345"}, ItemLookupQuery{}); err == nil {
+		t.Error("Unknown identifiers must be rejected")
+	}
+}
@@ -0,0 +1,65 @@
+package amazonpa
+
+import "strings"
+
+// Path returns the browse nodes from the root of the tree down to the node
+func (node BrowseNode) Path() []BrowseNode {
+	path := []BrowseNode{node}
+
+	for ancestors := node.Ancestors.BrowseNode; len(ancestors) > 0; ancestors = ancestors[0].Ancestors.BrowseNode {
+		path = append(path, ancestors[0])
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// Root returns the topmost ancestor of the node, or the node itself if it
+// has no ancestors
+func (node BrowseNode) Root() BrowseNode {
+	return node.Path()[0]
+}
+
+// Breadcrumb returns the names of the nodes in the path joined by sep.
+// The "Categories" nodes marked as IsCategoryRoot, which only group the
+// categories of a store, are left out.
+func (node BrowseNode) Breadcrumb(sep string) string {
+	var names []string
+
+	for _, ancestor := range node.Path() {
+		if !ancestor.IsCategoryRoot {
+			names = append(names, ancestor.Name)
+		}
+	}
+
+	return strings.Join(names, sep)
+}
+
+// CategoryPath returns the deepest browse node path of the item, or nil if
+// the item has no browse nodes
+func (item Item) CategoryPath() []BrowseNode {
+	var deepest []BrowseNode
+
+	for _, node := range item.BrowseNodes.BrowseNode {
+		if path := node.Path(); len(path) > len(deepest) {
+			deepest = path
+		}
+	}
+
+	return deepest
+}
+
+// TopItems returns the items of the top item set of the given type (e.g.
+// TopItemSetMostGifted), or nil if the set was not returned
+func (node BrowseNode) TopItems(setType string) []TopItem {
+	for _, set := range node.TopItemSets {
+		if set.Type == setType {
+			return set.TopItems
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package amazonpa
+
+import "encoding/json"
+
+// SearchBuilder builds ItemSearch queries with chained calls:
+//
+//	query, err := NewSearch(SearchIndexBooks).
+//		Keywords("go").
+//		Author("Pike").
+//		PriceBetween(1000, 5000).
+//		Sort(SortSalesRank).
+//		Groups(ResponseGroupLarge, ResponseGroupOffers).
+//		Build("US")
+//
+// A SearchBuilder can be encoded to JSON and decoded back, to store saved
+// searches.
+type SearchBuilder struct {
+	query ItemSearchQuery
+}
+
+// NewSearch returns a builder of queries on the given search index
+func NewSearch(index SearchIndex) *SearchBuilder {
+	return &SearchBuilder{query: ItemSearchQuery{SearchIndex: index}}
+}
+
+// Keywords sets the searched keywords
+func (builder *SearchBuilder) Keywords(keywords string) *SearchBuilder {
+	builder.query.Keywords = keywords
+	return builder
+}
+
+// Title sets the searched title
+func (builder *SearchBuilder) Title(title string) *SearchBuilder {
+	builder.query.Title = title
+	return builder
+}
+
+// Author sets the searched author
+func (builder *SearchBuilder) Author(author string) *SearchBuilder {
+	builder.query.Author = author
+	return builder
+}
+
+// Actor sets the searched actor
+func (builder *SearchBuilder) Actor(actor string) *SearchBuilder {
+	builder.query.Actor = actor
+	return builder
+}
+
+// Artist sets the searched artist
+func (builder *SearchBuilder) Artist(artist string) *SearchBuilder {
+	builder.query.Artist = artist
+	return builder
+}
+
+// Composer sets the searched composer
+func (builder *SearchBuilder) Composer(composer string) *SearchBuilder {
+	builder.query.Composer = composer
+	return builder
+}
+
+// Conductor sets the searched conductor
+func (builder *SearchBuilder) Conductor(conductor string) *SearchBuilder {
+	builder.query.Conductor = conductor
+	return builder
+}
+
+// Director sets the searched director
+func (builder *SearchBuilder) Director(director string) *SearchBuilder {
+	builder.query.Director = director
+	return builder
+}
+
+// Orchestra sets the searched orchestra
+func (builder *SearchBuilder) Orchestra(orchestra string) *SearchBuilder {
+	builder.query.Orchestra = orchestra
+	return builder
+}
+
+// Publisher sets the searched publisher
+func (builder *SearchBuilder) Publisher(publisher string) *SearchBuilder {
+	builder.query.Publisher = publisher
+	return builder
+}
+
+// Brand sets the searched brand
+func (builder *SearchBuilder) Brand(brand string) *SearchBuilder {
+	builder.query.Brand = brand
+	return builder
+}
+
+// Manufacturer sets the searched manufacturer
+func (builder *SearchBuilder) Manufacturer(manufacturer string) *SearchBuilder {
+	builder.query.Manufacturer = manufacturer
+	return builder
+}
+
+// Power sets a power search query
+func (builder *SearchBuilder) Power(power string) *SearchBuilder {
+	builder.query.Power = power
+	return builder
+}
+
+// BrowseNode restricts the search to a browse node
+func (builder *SearchBuilder) BrowseNode(id string) *SearchBuilder {
+	builder.query.BrowseNode = id
+	return builder
+}
+
+// AudienceRatings restricts the search to the given audience ratings
+func (builder *SearchBuilder) AudienceRatings(ratings ...string) *SearchBuilder {
+	builder.query.AudienceRatings = append([]string(nil), ratings...)
+	return builder
+}
+
+// Available restricts the search to the available items
+func (builder *SearchBuilder) Available() *SearchBuilder {
+	builder.query.Availability = "Available"
+	return builder
+}
+
+// Condition sets the condition of the returned offers
+func (builder *SearchBuilder) Condition(condition Condition) *SearchBuilder {
+	builder.query.Condition = condition
+	return builder
+}
+
+// MerchantID restricts the returned offers to a merchant
+func (builder *SearchBuilder) MerchantID(id string) *SearchBuilder {
+	builder.query.MerchantID = id
+	return builder
+}
+
+// PriceBetween restricts the search to the prices between min and max, in
+// the lowest currency denomination. A zero bound is not set.
+func (builder *SearchBuilder) PriceBetween(min, max int) *SearchBuilder {
+	builder.query.MinimumPrice = min
+	builder.query.MaximumPrice = max
+	return builder
+}
+
+// MinPercentageOff restricts the search to items discounted by at least
+// percentage
+func (builder *SearchBuilder) MinPercentageOff(percentage int) *SearchBuilder {
+	builder.query.MinPercentageOff = percentage
+	return builder
+}
+
+// Sort sets the sort order of the results
+func (builder *SearchBuilder) Sort(sort Sort) *SearchBuilder {
+	builder.query.Sort = sort
+	return builder
+}
+
+// Page sets the result page
+func (builder *SearchBuilder) Page(page int) *SearchBuilder {
+	builder.query.ItemPage = page
+	return builder
+}
+
+// Groups sets the response groups
+func (builder *SearchBuilder) Groups(groups ...ResponseGroup) *SearchBuilder {
+	builder.query.ResponseGroups = append([]ResponseGroup(nil), groups...)
+	return builder
+}
+
+// ReviewsSummary sets whether the reviews summary is included
+func (builder *SearchBuilder) ReviewsSummary(include bool) *SearchBuilder {
+	builder.query.IncludeReviewsSummary = Bool(include)
+	return builder
+}
+
+// TruncateReviewsAt sets the maximum length of the returned reviews
+func (builder *SearchBuilder) TruncateReviewsAt(length int) *SearchBuilder {
+	builder.query.TruncateReviewsAt = Int(length)
+	return builder
+}
+
+// Query returns the query built so far, without validating it
+func (builder *SearchBuilder) Query() ItemSearchQuery {
+	query := builder.query
+	query.AudienceRatings = append([]string(nil), query.AudienceRatings...)
+	query.ResponseGroups = append([]ResponseGroup(nil), query.ResponseGroups...)
+
+	if query.IncludeReviewsSummary != nil {
+		query.IncludeReviewsSummary = Bool(*query.IncludeReviewsSummary)
+	}
+
+	if query.TruncateReviewsAt != nil {
+		query.TruncateReviewsAt = Int(*query.TruncateReviewsAt)
+	}
+
+	return query
+}
+
+// Build returns the query after validating it for the marketplace of the
+// given region
+func (builder *SearchBuilder) Build(region string) (ItemSearchQuery, error) {
+	query := builder.Query()
+
+	return query, query.Validate(region)
+}
+
+// MarshalJSON encodes the query built so far
+func (builder SearchBuilder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(builder.query)
+}
+
+// UnmarshalJSON restores a query encoded with MarshalJSON
+func (builder *SearchBuilder) UnmarshalJSON(data []byte) error {
+	var query ItemSearchQuery
+	if err := json.Unmarshal(data, &query); err != nil {
+		return err
+	}
+
+	builder.query = query
+	return nil
+}
@@ -0,0 +1,77 @@
+package amazonpa
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchBuilder(t *testing.T) {
+	builder := NewSearch(SearchIndexBooks).
+		Keywords("go").
+		Author("Pike").
+		PriceBetween(1000, 5000).
+		Sort(SortSalesRank).
+		Groups(ResponseGroupLarge, ResponseGroupOffers)
+
+	query, err := builder.Build("US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ItemSearchQuery{
+		SearchIndex:    SearchIndexBooks,
+		Keywords:       "go",
+		Author:         "Pike",
+		MinimumPrice:   1000,
+		MaximumPrice:   5000,
+		Sort:           SortSalesRank,
+		ResponseGroups: []ResponseGroup{ResponseGroupLarge, ResponseGroupOffers},
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("Bad query %+v", query)
+	}
+
+	data, err := json.Marshal(builder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqualStr(t, string(data), `{"author":"Pike","keywords":"go","maximumPrice":5000,"minimumPrice":1000,"searchIndex":"Books","sort":"salesrank","responseGroups":["Large","Offers"]}`, "Bad JSON")
+
+	// Values and fields holding a builder encode the same way
+	stored := struct{ Search SearchBuilder }{*builder}
+	data, err = json.Marshal(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"keywords":"go"`) {
+		t.Errorf("Bad JSON of a builder value %s", data)
+	}
+
+	var saved SearchBuilder
+	data, _ = json.Marshal(builder)
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(saved.Query(), expected) {
+		t.Errorf("Bad restored query %+v", saved.Query())
+	}
+
+	if _, err := NewSearch(SearchIndexAll).Keywords("go").Sort(SortSalesRank).Build("US"); err == nil {
+		t.Error("Build must validate the query")
+	}
+}
+
+func TestSearchBuilderQueryCopy(t *testing.T) {
+	builder := NewSearch(SearchIndexBooks).Keywords("go").ReviewsSummary(true).TruncateReviewsAt(100)
+
+	first := builder.Query()
+	*first.IncludeReviewsSummary = false
+	*first.TruncateReviewsAt = 0
+
+	second := builder.Query()
+	if !*second.IncludeReviewsSummary || *second.TruncateReviewsAt != 100 {
+		t.Errorf("Queries share the options of the builder %+v", second)
+	}
+}
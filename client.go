@@ -1,10 +1,10 @@
 package amazonpa
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
-	"encoding/xml"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -15,67 +15,139 @@ import (
 
 // ItemLookupQuery describes the allowed parameters for a ItemLookup request
 type ItemLookupQuery struct {
-	Condition             string
-	IDType                string
-	IncludeReviewsSummary string
-	ItemIDs               []string
-	MerchantID            string
-	RelatedItemPage       string
-	RelationshipType      string
-	SearchIndex           string
-	TruncateReviewsAt     string
-	VariationPage         string
-	ResponseGroups        []string
+	Condition             Condition       `json:"condition,omitempty"`
+	IDType                IDType          `json:"idType,omitempty"`
+	IncludeReviewsSummary *bool           `json:"includeReviewsSummary,omitempty"`
+	ItemIDs               []string        `json:"itemIds,omitempty"`
+	MerchantID            string          `json:"merchantId,omitempty"`
+	RelatedItemPage       string          `json:"relatedItemPage,omitempty"`
+	RelationshipType      string          `json:"relationshipType,omitempty"`
+	SearchIndex           SearchIndex     `json:"searchIndex,omitempty"`
+	TruncateReviewsAt     *int            `json:"truncateReviewsAt,omitempty"`
+	VariationPage         string          `json:"variationPage,omitempty"`
+	ResponseGroups        []ResponseGroup `json:"responseGroups,omitempty"`
+}
+
+// Parameters returns the request parameters corresponding to the query
+func (query ItemLookupQuery) Parameters() map[string]string {
+	return map[string]string{
+		"Condition":             string(query.Condition),
+		"IdType":                string(query.IDType),
+		"IncludeReviewsSummary": formatBool(query.IncludeReviewsSummary),
+		"ItemId":                strings.Join(query.ItemIDs, ","),
+		"MerchantId":            query.MerchantID,
+		"RelatedItemPage":       query.RelatedItemPage,
+		"RelationshipType":      query.RelationshipType,
+		"SearchIndex":           string(query.SearchIndex),
+		"TruncateReviewsAt":     formatOptionalInt(query.TruncateReviewsAt),
+		"VariationPage":         query.VariationPage,
+		"ResponseGroup":         joinResponseGroups(query.ResponseGroups),
+	}
 }
 
 // ItemSearchQuery describes the allowed parameters for a ItemSearch request
 type ItemSearchQuery struct {
-	Actor                 string
-	Artist                string
-	AudienceRatings       []string
-	Author                string
-	Availability          string
-	Brand                 string
-	BrowseNode            string
-	Composer              string
-	Condition             string
-	Conductor             string
-	Director              string
-	IncludeReviewsSummary string
-	ItemPage              string
-	Keywords              string
-	Manufacturer          string
-	MaximumPrice          string
-	MerchantID            string
-	MinimumPrice          string
-	MinPercentageOff      string
-	Orchestra             string
-	Power                 string
-	Publisher             string
-	RelatedItemPage       string
-	RelationshipType      string
-	SearchIndex           string
-	Sort                  string
-	Title                 string
-	TruncateReviewsAt     string
-	VariationPage         string
-	ResponseGroups        []string
+	Actor                 string          `json:"actor,omitempty"`
+	Artist                string          `json:"artist,omitempty"`
+	AudienceRatings       []string        `json:"audienceRatings,omitempty"`
+	Author                string          `json:"author,omitempty"`
+	Availability          string          `json:"availability,omitempty"`
+	Brand                 string          `json:"brand,omitempty"`
+	BrowseNode            string          `json:"browseNode,omitempty"`
+	Composer              string          `json:"composer,omitempty"`
+	Condition             Condition       `json:"condition,omitempty"`
+	Conductor             string          `json:"conductor,omitempty"`
+	Director              string          `json:"director,omitempty"`
+	IncludeReviewsSummary *bool           `json:"includeReviewsSummary,omitempty"`
+	ItemPage              int             `json:"itemPage,omitempty"`
+	Keywords              string          `json:"keywords,omitempty"`
+	Manufacturer          string          `json:"manufacturer,omitempty"`
+	MaximumPrice          int             `json:"maximumPrice,omitempty"`
+	MerchantID            string          `json:"merchantId,omitempty"`
+	MinimumPrice          int             `json:"minimumPrice,omitempty"`
+	MinPercentageOff      int             `json:"minPercentageOff,omitempty"`
+	Orchestra             string          `json:"orchestra,omitempty"`
+	Power                 string          `json:"power,omitempty"`
+	Publisher             string          `json:"publisher,omitempty"`
+	RelatedItemPage       string          `json:"relatedItemPage,omitempty"`
+	RelationshipType      string          `json:"relationshipType,omitempty"`
+	SearchIndex           SearchIndex     `json:"searchIndex,omitempty"`
+	Sort                  Sort            `json:"sort,omitempty"`
+	Title                 string          `json:"title,omitempty"`
+	TruncateReviewsAt     *int            `json:"truncateReviewsAt,omitempty"`
+	VariationPage         string          `json:"variationPage,omitempty"`
+	ResponseGroups        []ResponseGroup `json:"responseGroups,omitempty"`
+}
+
+// Parameters returns the request parameters corresponding to the query
+func (query ItemSearchQuery) Parameters() map[string]string {
+	return map[string]string{
+		"Actor":                 query.Actor,
+		"Artist":                query.Artist,
+		"AudienceRating":        strings.Join(query.AudienceRatings, ","),
+		"Author":                query.Author,
+		"Availability":          query.Availability,
+		"Brand":                 query.Brand,
+		"BrowseNode":            query.BrowseNode,
+		"Composer":              query.Composer,
+		"Condition":             string(query.Condition),
+		"Conductor":             query.Conductor,
+		"Director":              query.Director,
+		"IncludeReviewsSummary": formatBool(query.IncludeReviewsSummary),
+		"ItemPage":              formatInt(query.ItemPage),
+		"Keywords":              query.Keywords,
+		"Manufacturer":          query.Manufacturer,
+		"MaximumPrice":          formatInt(query.MaximumPrice),
+		"MerchantId":            query.MerchantID,
+		"MinimumPrice":          formatInt(query.MinimumPrice),
+		"MinPercentageOff":      formatInt(query.MinPercentageOff),
+		"Orchestra":             query.Orchestra,
+		"Power":                 query.Power,
+		"Publisher":             query.Publisher,
+		"RelatedItemPage":       query.RelatedItemPage,
+		"RelationshipType":      query.RelationshipType,
+		"SearchIndex":           string(query.SearchIndex),
+		"Sort":                  string(query.Sort),
+		"Title":                 query.Title,
+		"TruncateReviewsAt":     formatOptionalInt(query.TruncateReviewsAt),
+		"VariationPage":         query.VariationPage,
+		"ResponseGroup":         joinResponseGroups(query.ResponseGroups),
+	}
 }
 
 type BrowseNodeLookupQuery struct {
-	BrowseNodeID   string
-	ResponseGroups []string
+	BrowseNodeID   string          `json:"browseNodeId,omitempty"`
+	ResponseGroups []ResponseGroup `json:"responseGroups,omitempty"`
+}
+
+// Parameters returns the request parameters corresponding to the query
+func (query BrowseNodeLookupQuery) Parameters() map[string]string {
+	return map[string]string{
+		"BrowseNodeId":  query.BrowseNodeID,
+		"ResponseGroup": joinResponseGroups(query.ResponseGroups),
+	}
 }
 
 // Client provides the functions to interact with the API
 type Client struct {
-	config     Config
-	httpClient *http.Client
+	config      Config
+	httpClient  *http.Client
+	limiter     limiter
+	credentials CredentialsProvider
+	middleware  []Middleware
+	metrics     Metrics
+	tracer      Tracer
+	quota       *quotaTracker
 }
 
 // NewClient returns a new Client
 func NewClient(config Config) *Client {
-	c := Client{config: config, httpClient: http.DefaultClient}
+	c := Client{
+		config:      config,
+		httpClient:  http.DefaultClient,
+		credentials: StaticCredentials{AccessKey: config.AccessKey, AccessSecret: config.AccessSecret},
+		quota:       newQuotaTracker(),
+	}
 
 	return &c
 }
@@ -85,6 +157,37 @@ func (client *Client) SetHTTPClient(h *http.Client) {
 	client.httpClient = h
 }
 
+// SetCredentialsProvider sets the provider of the credentials, which is
+// consulted every time a request is signed instead of using the keys of
+// the Config
+func (client *Client) SetCredentialsProvider(provider CredentialsProvider) {
+	client.credentials = provider
+}
+
+// SetRateLimit limits the rate of the requests performed by the client,
+// which are delayed to stay within requestsPerSecond. A non-positive value
+// removes the limit.
+func (client *Client) SetRateLimit(requestsPerSecond float64) {
+	if requestsPerSecond <= 0 {
+		client.limiter = nil
+		return
+	}
+
+	client.limiter = newRateLimiter(requestsPerSecond)
+}
+
+// SetScheduler makes the requests wait for their turn in the scheduler,
+// according to the priority of their context, instead of the rate limit
+// set with SetRateLimit
+func (client *Client) SetScheduler(scheduler *Scheduler) {
+	if scheduler == nil {
+		client.limiter = nil
+		return
+	}
+
+	client.limiter = scheduler
+}
+
 // NewRequest returns a request with basic parameters
 func (client Client) NewRequest(operation string) *Request {
 
@@ -111,26 +214,135 @@ func (client Client) NewRequest(operation string) *Request {
 	return &request
 }
 
-// SignRequest produces the signature for the given query string
+// SignRequest produces the signature for the given query string. If the
+// credentials cannot be obtained the request is left unsigned; use
+// TrySignRequest to get the error.
 func (client Client) SignRequest(request *Request) {
+	client.TrySignRequest(request)
+}
+
+// TrySignRequest produces the signature for the given query string, using
+// the credentials of the provider, and returns the error of the provider
+func (client Client) TrySignRequest(request *Request) error {
+	credentials, err := client.credentials.Credentials()
+
+	if err != nil {
+		request.signature = ""
+		return err
+	}
+
+	request.SetParameter("AWSAccessKeyId", credentials.AccessKey)
+
+	if credentials.AssociateTag != "" {
+		request.SetParameter("AssociateTag", credentials.AssociateTag)
+	}
+
+	request.credentials = credentials
+
 	signable := fmt.Sprintf("GET\n%s\n%s\n%s", request.endpoint, request.endpointURI, request.QueryString())
 
-	hasher := hmac.New(sha256.New, []byte(client.config.AccessSecret))
+	hasher := hmac.New(sha256.New, []byte(credentials.AccessSecret))
 	hasher.Write([]byte(signable))
 
 	request.signature = base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+
+	return nil
+}
+
+// PresignURL returns a signed URL for the given operation and parameters,
+// which can be handed over to other systems to perform the request. If
+// expiresAt is zero the URL carries the current Timestamp, otherwise it is
+// signed with an Expires parameter and is valid until expiresAt.
+func (client Client) PresignURL(operation string, params map[string]string, expiresAt time.Time) (string, error) {
+	if _, ok := params["Timestamp"]; ok {
+		return "", errors.New("amazonpa: the Timestamp of a presigned URL cannot be set in the parameters")
+	}
+
+	if _, ok := params["Expires"]; ok {
+		return "", errors.New("amazonpa: the Expires of a presigned URL must be set with expiresAt")
+	}
+
+	request := client.NewRequest(operation)
+
+	for key, value := range params {
+		request.SetParameter(key, value)
+	}
+
+	if !expiresAt.IsZero() {
+		if !expiresAt.After(time.Now()) {
+			return "", errors.New("amazonpa: presigned URL expiration is in the past")
+		}
+
+		delete(request.parameters, "Timestamp")
+		request.SetParameter("Expires", expiresAt.UTC().Format(time.RFC3339))
+	}
+
+	if err := client.TrySignRequest(request); err != nil {
+		return "", err
+	}
+
+	return request.SignedURL()
 }
 
 // ProcessRequest takes a request and queries the API
 func (client Client) ProcessRequest(request *Request) ([]byte, error) {
+	return client.ProcessRequestContext(context.Background(), request)
+}
 
-	// Sign the request
-	client.SignRequest(request)
+// ProcessRequestContext takes a request and queries the API, giving up
+// when the context is done
+func (client Client) ProcessRequestContext(ctx context.Context, request *Request) ([]byte, error) {
+
+	exchange := Exchange{Request: request}
+	err := client.do(ctx, &exchange)
 
-	// Execute the HTTP request
-	var httpResponse *http.Response
-	var err error
-	var contents []byte
+	return exchange.Body, err
+}
+
+// readBody reads the whole body of the response
+func readBody(httpResponse *http.Response) ([]byte, error) {
+	contents, err := ioutil.ReadAll(httpResponse.Body)
+
+	if err != nil {
+		return nil, errors.New("amazonpa: error while reading the server response")
+	}
+
+	return contents, nil
+}
+
+// reportResult notifies the credentials provider of the outcome of a
+// request, if it keeps track of them
+func (client Client) reportResult(request *Request, err error) {
+	if reporter, ok := client.credentials.(CredentialsReporter); ok {
+		reporter.ReportResult(request.credentials, err)
+	}
+}
+
+// sendRequest signs the request of the exchange and executes it, leaving
+// the response body to be read and closed by the caller
+func (client Client) sendRequest(ctx context.Context, exchange *Exchange) (*http.Response, error) {
+
+	request := exchange.Request
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Wait for the rate limiter
+	if client.limiter != nil {
+		start := time.Now()
+		err := client.limiter.Wait(ctx)
+		client.Metrics().ObserveRateLimitWait(request.Operation(), time.Since(start))
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Sign the request
+	if err := client.TrySignRequest(request); err != nil {
+		return nil, err
+	}
 
 	requestURL, err := request.SignedURL()
 
@@ -138,48 +350,56 @@ func (client Client) ProcessRequest(request *Request) ([]byte, error) {
 		return nil, errors.New("amazonpa: cannot get the signed request URL")
 	}
 
-	httpResponse, err = client.httpClient.Get(requestURL)
+	exchange.SignedURL = requestURL
+
+	// Count the request against the quota of the access key
+	if err := client.quota.acquire(request.credentials.AccessKey, time.Now()); err != nil {
+		return nil, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 
 	if err != nil {
-		return nil, errors.New("amazonpa: error processing the http request")
+		return nil, errors.New("amazonpa: cannot create the http request")
+	}
+
+	for key, values := range exchange.Header {
+		httpRequest.Header[key] = values
 	}
 
-	contents, err = ioutil.ReadAll(httpResponse.Body)
-	httpResponse.Body.Close()
+	exchange.sent = time.Now()
+	httpResponse, err := client.httpClient.Do(httpRequest)
 
 	if err != nil {
-		return nil, errors.New("amazonpa: error while reading the server response")
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, errors.New("amazonpa: error processing the http request")
 	}
 
-	return contents, nil
+	return httpResponse, nil
 }
 
 // ItemLookup performs an ItemLookup request
 func (client Client) ItemLookup(query ItemLookupQuery) (*ItemLookupResponse, error) {
+	return client.ItemLookupContext(context.Background(), query)
+}
+
+// ItemLookupContext performs an ItemLookup request with a context
+func (client Client) ItemLookupContext(ctx context.Context, query ItemLookupQuery) (*ItemLookupResponse, error) {
 
 	request := client.NewRequest("ItemLookup")
 
-	request.SetParameter("Condition", query.Condition)
-	request.SetParameter("IdType", query.IDType)
-	request.SetParameter("IncludeReviewsSummary", query.IncludeReviewsSummary)
-	request.SetParameter("ItemId", strings.Join(query.ItemIDs, ","))
-	request.SetParameter("MerchantId", query.MerchantID)
-	request.SetParameter("RelatedItemPage", query.RelatedItemPage)
-	request.SetParameter("RelationshipType", query.RelationshipType)
-	request.SetParameter("SearchIndex", query.SearchIndex)
-	request.SetParameter("TruncateReviewsAt", query.TruncateReviewsAt)
-	request.SetParameter("VariationPage", query.VariationPage)
-	request.SetParameter("ResponseGroup", strings.Join(query.ResponseGroups, ","))
+	for key, value := range query.Parameters() {
+		request.SetParameter(key, value)
+	}
 
-	xmlData, err := client.ProcessRequest(request)
+	var response ItemLookupResponse
 
-	if err != nil {
+	if err := client.do(ctx, &Exchange{Request: request, Result: &response}); err != nil {
 		return nil, err
 	}
 
-	var response ItemLookupResponse
-	xml.Unmarshal(xmlData, &response)
-
 	if response.Items.Request.IsValid != true {
 		return &response, errors.New("amazonpa: request is invalid")
 	}
@@ -187,50 +407,30 @@ func (client Client) ItemLookup(query ItemLookupQuery) (*ItemLookupResponse, err
 	return &response, nil
 }
 
-// ItemLookup performs an ItemLookup request
+// ItemSearch performs an ItemSearch request, after validating the query
+// against the search index rules of the marketplace
 func (client Client) ItemSearch(query ItemSearchQuery) (*ItemSearchResponse, error) {
+	return client.ItemSearchContext(context.Background(), query)
+}
 
-	request := client.NewRequest("ItemSearch")
-
-	request.SetParameter("Actor", query.Actor)
-	request.SetParameter("Artist", query.Artist)
-	request.SetParameter("AudienceRating", strings.Join(query.AudienceRatings, ","))
-	request.SetParameter("Author", query.Author)
-	request.SetParameter("Availability", query.Availability)
-	request.SetParameter("Brand", query.Brand)
-	request.SetParameter("BrowseNode", query.BrowseNode)
-	request.SetParameter("Composer", query.Composer)
-	request.SetParameter("Condition", query.Condition)
-	request.SetParameter("Conductor", query.Conductor)
-	request.SetParameter("Director", query.Director)
-	request.SetParameter("IncludeReviewsSummary", query.IncludeReviewsSummary)
-	request.SetParameter("ItemPage", query.ItemPage)
-	request.SetParameter("Keywords", query.Keywords)
-	request.SetParameter("Manufacturer", query.Manufacturer)
-	request.SetParameter("MaximumPrice", query.MaximumPrice)
-	request.SetParameter("MerchantId", query.MerchantID)
-	request.SetParameter("MinimumPrice", query.MinimumPrice)
-	request.SetParameter("MinPercentageOff", query.MinPercentageOff)
-	request.SetParameter("Orchestra", query.Orchestra)
-	request.SetParameter("Power", query.Power)
-	request.SetParameter("Publisher", query.Publisher)
-	request.SetParameter("RelatedItemPage", query.RelatedItemPage)
-	request.SetParameter("RelationshipType", query.RelationshipType)
-	request.SetParameter("SearchIndex", query.SearchIndex)
-	request.SetParameter("Sort", query.Sort)
-	request.SetParameter("Title", query.Title)
-	request.SetParameter("TruncateReviewsAt", query.TruncateReviewsAt)
-	request.SetParameter("VariationPage", query.VariationPage)
-	request.SetParameter("ResponseGroup", strings.Join(query.ResponseGroups, ","))
-
-	xmlData, err := client.ProcessRequest(request)
+// ItemSearchContext performs an ItemSearch request with a context
+func (client Client) ItemSearchContext(ctx context.Context, query ItemSearchQuery) (*ItemSearchResponse, error) {
 
-	if err != nil {
+	if err := query.Validate(client.config.Region); err != nil {
 		return nil, err
 	}
 
+	request := client.NewRequest("ItemSearch")
+
+	for key, value := range query.Parameters() {
+		request.SetParameter(key, value)
+	}
+
 	var response ItemSearchResponse
-	xml.Unmarshal(xmlData, &response)
+
+	if err := client.do(ctx, &Exchange{Request: request, Result: &response}); err != nil {
+		return nil, err
+	}
 
 	if response.Items.Request.IsValid != true {
 		return &response, errors.New("amazonpa: request is invalid")
@@ -239,23 +439,26 @@ func (client Client) ItemSearch(query ItemSearchQuery) (*ItemSearchResponse, err
 	return &response, nil
 }
 
-// ItemLookup performs an ItemLookup request
+// BrowseNodeLookup performs a BrowseNodeLookup request
 func (client Client) BrowseNodeLookup(query BrowseNodeLookupQuery) (*BrowseNodeLookupResponse, error) {
+	return client.BrowseNodeLookupContext(context.Background(), query)
+}
+
+// BrowseNodeLookupContext performs a BrowseNodeLookup request with a context
+func (client Client) BrowseNodeLookupContext(ctx context.Context, query BrowseNodeLookupQuery) (*BrowseNodeLookupResponse, error) {
 
 	request := client.NewRequest("BrowseNodeLookup")
 
-	request.SetParameter("BrowseNodeId", query.BrowseNodeID)
-	request.SetParameter("ResponseGroup", strings.Join(query.ResponseGroups, ","))
+	for key, value := range query.Parameters() {
+		request.SetParameter(key, value)
+	}
 
-	xmlData, err := client.ProcessRequest(request)
+	var response BrowseNodeLookupResponse
 
-	if err != nil {
+	if err := client.do(ctx, &Exchange{Request: request, Result: &response}); err != nil {
 		return nil, err
 	}
 
-	var response BrowseNodeLookupResponse
-	xml.Unmarshal(xmlData, &response)
-
 	if response.BrowseNodes.Request.IsValid != true {
 		return &response, errors.New("amazonpa: request is invalid")
 	}
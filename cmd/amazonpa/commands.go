@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattbit/amazonpa"
+)
+
+func lookupCommand() command {
+	flags := flag.NewFlagSet("lookup", flag.ContinueOnError)
+	var query amazonpa.ItemLookupQuery
+	var groups string
+
+	flags.StringVar((*string)(&query.IDType), "idtype", "", "identifier type: ASIN, SKU, UPC, EAN or ISBN (detected if not set)")
+	flags.StringVar((*string)(&query.SearchIndex), "index", "", "search index (required for non-ASIN identifiers)")
+	flags.StringVar((*string)(&query.Condition), "condition", "", "offer condition: All, New, Used, Collectible or Refurbished")
+	flags.StringVar(&query.MerchantID, "merchant", "", "merchant ID")
+	flags.Var(boolFlag{&query.IncludeReviewsSummary}, "reviews-summary", "include the reviews summary")
+	flags.Var(intFlag{&query.TruncateReviewsAt}, "truncate-reviews", "maximum length of the reviews (0 for the full reviews)")
+	flags.StringVar(&query.RelationshipType, "relationship", "", "relationship type")
+	flags.StringVar(&query.RelatedItemPage, "related-page", "", "page of related items")
+	flags.StringVar(&query.VariationPage, "variation-page", "", "page of variations")
+	flags.StringVar(&groups, "groups", "Large", "comma separated response groups")
+
+	return command{flags, func(env *environment, args []string) error {
+		if len(args) == 0 {
+			return errors.New("lookup: missing item IDs")
+		}
+
+		args, err := env.resolveProductURLs(args)
+		if err != nil {
+			return err
+		}
+		query.ItemIDs = args
+		query.ResponseGroups = responseGroups(groups)
+
+		if env.format == "xml" {
+			return env.printRaw("ItemLookup", query.Parameters())
+		}
+
+		var items []amazonpa.Item
+
+		// Without an explicit type, mixed identifiers are detected and grouped
+		if query.IDType == "" {
+			items, err = env.client.LookupIdentifiers(args, query)
+		} else {
+			items, err = env.client.LookupItems(query)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if env.format == "json" {
+			return env.printJSON(items)
+		}
+
+		return env.printItems(items)
+	}}
+}
+
+func searchCommand() command {
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	var query amazonpa.ItemSearchQuery
+	var groups, audienceRatings string
+	var exporting exportFlags
+
+	flags.StringVar((*string)(&query.SearchIndex), "index", "All", "search index")
+	flags.StringVar(&query.Keywords, "keywords", "", "keywords (defaults to the arguments)")
+	flags.StringVar(&query.Title, "title", "", "title")
+	flags.StringVar(&query.Author, "author", "", "author")
+	flags.StringVar(&query.Actor, "actor", "", "actor")
+	flags.StringVar(&query.Artist, "artist", "", "artist")
+	flags.StringVar(&query.Composer, "composer", "", "composer")
+	flags.StringVar(&query.Conductor, "conductor", "", "conductor")
+	flags.StringVar(&query.Director, "director", "", "director")
+	flags.StringVar(&query.Orchestra, "orchestra", "", "orchestra")
+	flags.StringVar(&query.Publisher, "publisher", "", "publisher")
+	flags.StringVar(&query.Brand, "brand", "", "brand")
+	flags.StringVar(&query.Manufacturer, "manufacturer", "", "manufacturer")
+	flags.StringVar(&query.BrowseNode, "browse-node", "", "browse node ID")
+	flags.StringVar(&query.Power, "power", "", "power search query")
+	flags.StringVar(&query.Availability, "availability", "", "availability: Available")
+	flags.StringVar((*string)(&query.Condition), "condition", "", "offer condition: All, New, Used, Collectible or Refurbished")
+	flags.StringVar(&query.MerchantID, "merchant", "", "merchant ID")
+	flags.IntVar(&query.MinimumPrice, "min-price", 0, "minimum price in the lowest currency denomination")
+	flags.IntVar(&query.MaximumPrice, "max-price", 0, "maximum price in the lowest currency denomination")
+	flags.IntVar(&query.MinPercentageOff, "min-percentage-off", 0, "minimum percentage off")
+	flags.StringVar((*string)(&query.Sort), "sort", "", "sort order")
+	flags.IntVar(&query.ItemPage, "page", 0, "result page")
+	flags.Var(boolFlag{&query.IncludeReviewsSummary}, "reviews-summary", "include the reviews summary")
+	flags.Var(intFlag{&query.TruncateReviewsAt}, "truncate-reviews", "maximum length of the reviews (0 for the full reviews)")
+	flags.StringVar(&query.RelationshipType, "relationship", "", "relationship type")
+	flags.StringVar(&query.RelatedItemPage, "related-page", "", "page of related items")
+	flags.StringVar(&query.VariationPage, "variation-page", "", "page of variations")
+	flags.StringVar(&audienceRatings, "audience-ratings", "", "comma separated audience ratings")
+	flags.StringVar(&groups, "groups", "Medium", "comma separated response groups")
+	flags.StringVar(&exporting.path, "export", "", "export the items of all the result pages to this file")
+	flags.StringVar(&exporting.format, "export-format", "", "export format: csv or tsv (defaults to the file extension)")
+	flags.StringVar(&exporting.columns, "columns", "", "comma separated columns to export (e.g. ASIN,Title,ListPrice)")
+	flags.IntVar(&exporting.maxPages, "pages", 0, "maximum number of result pages to export (0 for all)")
+
+	return command{flags, func(env *environment, args []string) error {
+		if query.Keywords == "" {
+			query.Keywords = strings.Join(args, " ")
+		}
+		query.AudienceRatings = splitList(audienceRatings)
+		query.ResponseGroups = responseGroups(groups)
+
+		if exporting.path != "" {
+			return env.exportSearch(query, exporting)
+		}
+
+		if env.format == "xml" {
+			return env.printRaw("ItemSearch", query.Parameters())
+		}
+
+		response, err := env.client.ItemSearch(query)
+		if err != nil {
+			return err
+		}
+
+		if env.format == "json" {
+			return env.printJSON(response)
+		}
+
+		return env.printItems(response.Items.Items)
+	}}
+}
+
+func browseCommand() command {
+	flags := flag.NewFlagSet("browse", flag.ContinueOnError)
+	var query amazonpa.BrowseNodeLookupQuery
+	var groups string
+
+	flags.StringVar(&groups, "groups", "BrowseNodeInfo", "comma separated response groups")
+
+	return command{flags, func(env *environment, args []string) error {
+		if len(args) != 1 {
+			return errors.New("browse: expected exactly one browse node ID")
+		}
+		query.BrowseNodeID = args[0]
+		query.ResponseGroups = responseGroups(groups)
+
+		if env.format == "xml" {
+			return env.printRaw("BrowseNodeLookup", query.Parameters())
+		}
+
+		response, err := env.client.BrowseNodeLookup(query)
+		if err != nil {
+			return err
+		}
+
+		if env.format == "json" {
+			return env.printJSON(response)
+		}
+
+		return env.printBrowseNode(response.BrowseNodes.BrowseNode)
+	}}
+}
+
+func signCommand() command {
+	flags := flag.NewFlagSet("sign", flag.ContinueOnError)
+	var expires time.Duration
+
+	flags.DurationVar(&expires, "expires", 0, "sign with an Expires parameter this far in the future instead of a Timestamp")
+
+	return command{flags, func(env *environment, args []string) error {
+		if len(args) == 0 {
+			return errors.New("sign: missing operation")
+		}
+
+		params := map[string]string{}
+		for _, arg := range args[1:] {
+			parts := strings.SplitN(arg, "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("sign: invalid parameter %q, expected NAME=VALUE", arg)
+			}
+			params[parts[0]] = parts[1]
+		}
+
+		var expiresAt time.Time
+		if expires > 0 {
+			expiresAt = time.Now().Add(expires)
+		}
+
+		signedURL, err := env.client.PresignURL(args[0], params, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(env.stdout, signedURL)
+		return err
+	}}
+}
+
+// resolveProductURLs replaces the product URLs among the arguments with
+// their ASIN, switching to the marketplace of the URLs
+func (env *environment) resolveProductURLs(args []string) ([]string, error) {
+	region := ""
+	resolved := make([]string, len(args))
+
+	for i, arg := range args {
+		if !strings.Contains(arg, "/") {
+			resolved[i] = arg
+			continue
+		}
+
+		asin, urlRegion, err := env.client.ResolveProductURL(context.Background(), arg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", arg, err)
+		}
+
+		if region != "" && urlRegion != region {
+			return nil, errors.New("lookup: product URLs from different marketplaces")
+		}
+		region = urlRegion
+		resolved[i] = asin
+	}
+
+	if region != "" && region != env.config.Region {
+		env.config.Region = region
+		env.client = amazonpa.NewClient(env.config)
+	}
+
+	return resolved, nil
+}
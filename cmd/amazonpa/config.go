@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattbit/amazonpa"
+)
+
+// fileConfig describes the JSON configuration file read by the tool
+type fileConfig struct {
+	AccessKey    string `json:"access_key"`
+	AccessSecret string `json:"access_secret"`
+	AssociateTag string `json:"associate_tag"`
+	Region       string `json:"region"`
+	Insecure     bool   `json:"insecure"`
+}
+
+// defaultConfigPath returns the configuration file used when none is given
+func defaultConfigPath() string {
+	if path := os.Getenv("AMAZONPA_CONFIG"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".amazonpa.json")
+}
+
+// loadConfig builds the client configuration from the configuration file
+// and the environment, the latter taking precedence
+func loadConfig(path string, explicit bool) (amazonpa.Config, error) {
+	config := amazonpa.Config{Region: "US", Secure: true}
+
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+
+		switch {
+		case err == nil:
+			var file fileConfig
+			if err := json.Unmarshal(data, &file); err != nil {
+				return config, errors.New("cannot parse config file " + path + ": " + err.Error())
+			}
+			config.AccessKey = file.AccessKey
+			config.AccessSecret = file.AccessSecret
+			config.AssociateTag = file.AssociateTag
+			config.Secure = !file.Insecure
+			if file.Region != "" {
+				config.Region = file.Region
+			}
+		case explicit || !os.IsNotExist(err):
+			return config, err
+		}
+	}
+
+	env := map[string]*string{
+		"AMAZONPA_ACCESS_KEY":    &config.AccessKey,
+		"AMAZONPA_ACCESS_SECRET": &config.AccessSecret,
+		"AMAZONPA_ASSOCIATE_TAG": &config.AssociateTag,
+		"AMAZONPA_REGION":        &config.Region,
+	}
+	for name, field := range env {
+		if value := os.Getenv(name); value != "" {
+			*field = value
+		}
+	}
+
+	// Regions are upper case, like in the -region flag
+	config.Region = strings.ToUpper(config.Region)
+
+	return config, nil
+}
+
+// checkConfig verifies that the configuration can be used to sign requests
+func checkConfig(config amazonpa.Config) error {
+	if config.AccessKey == "" || config.AccessSecret == "" {
+		return errors.New("missing credentials: set AMAZONPA_ACCESS_KEY and AMAZONPA_ACCESS_SECRET or use a config file")
+	}
+
+	if _, ok := amazonpa.Endpoints[config.Region]; !ok {
+		return errors.New("unknown region " + config.Region)
+	}
+
+	return nil
+}
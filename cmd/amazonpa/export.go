@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattbit/amazonpa"
+	"github.com/mattbit/amazonpa/export"
+)
+
+// exportFlags are the flags controlling the export of search results
+type exportFlags struct {
+	path     string
+	format   string
+	columns  string
+	maxPages int
+}
+
+// exportSearch writes the items of every result page of the query to a file
+func (env *environment) exportSearch(query amazonpa.ItemSearchQuery, flags exportFlags) error {
+	columns, err := export.ParseColumns(flags.columns)
+	if err != nil {
+		return err
+	}
+
+	format := flags.format
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(flags.path)), ".")
+	}
+
+	// Check the format before creating the file, which truncates it
+	var newWriter func(io.Writer, []export.Column) *export.Writer
+	switch format {
+	case "csv":
+		newWriter = export.NewCSVWriter
+	case "tsv", "tab", "txt":
+		newWriter = export.NewTSVWriter
+	default:
+		return fmt.Errorf("unknown export format %q, use csv or tsv", format)
+	}
+
+	file, err := os.Create(flags.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := newWriter(file, columns)
+
+	if query.ItemPage == 0 {
+		query.ItemPage = 1
+	}
+
+	// The API only returns the first pages of the results
+	lastPage := 0
+	if rules, ok := amazonpa.SearchIndexes[env.config.Region][query.SearchIndex]; ok {
+		lastPage = rules.MaxItemPage
+	}
+
+	count, err := env.writePages(writer, query, flags.maxPages, lastPage)
+
+	// Keep the rows of the pages fetched before an error
+	if flushErr := writer.Flush(); err == nil {
+		err = flushErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(env.stdout, "Exported %d items to %s\n", count, flags.path)
+	return err
+}
+
+// writePages writes the items of up to maxPages result pages, stopping at
+// lastPage if it is set, and returns the number of items written
+func (env *environment) writePages(writer *export.Writer, query amazonpa.ItemSearchQuery, maxPages, lastPage int) (int, error) {
+	count := 0
+
+	for pages := 0; maxPages <= 0 || pages < maxPages; pages++ {
+
+		response, err := env.client.ItemSearch(query)
+		if err != nil {
+			return count, err
+		}
+
+		if err := writer.Write(response.Items.Items); err != nil {
+			return count, err
+		}
+		count += len(response.Items.Items)
+
+		if query.ItemPage >= response.Items.TotalPages || len(response.Items.Items) == 0 {
+			break
+		}
+
+		if lastPage > 0 && query.ItemPage >= lastPage {
+			break
+		}
+		query.ItemPage++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,220 @@
+// Command amazonpa queries the Amazon Product Advertising API from the
+// command line.
+//
+// Usage:
+//
+//	amazonpa lookup [flags] ITEMID|URL...
+//	amazonpa search [flags] [KEYWORDS...]
+//	amazonpa browse [flags] NODEID
+//	amazonpa sign [-expires DURATION] OPERATION [NAME=VALUE...]
+//
+// Credentials are read from ~/.amazonpa.json (or the file given by -config
+// or AMAZONPA_CONFIG) and from the AMAZONPA_ACCESS_KEY,
+// AMAZONPA_ACCESS_SECRET, AMAZONPA_ASSOCIATE_TAG and AMAZONPA_REGION
+// environment variables, which take precedence over the file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattbit/amazonpa"
+)
+
+const usage = `Usage: amazonpa COMMAND [flags] [arguments]
+
+Commands:
+  lookup   look up items by ASIN, other identifiers or product URL
+  search   search items
+  browse   look up a browse node
+  sign     print a presigned URL of an arbitrary request
+
+Run "amazonpa COMMAND -h" for the flags of a command.
+`
+
+// command is a subcommand of the tool
+type command struct {
+	flags *flag.FlagSet
+	run   func(env *environment, args []string) error
+}
+
+// environment holds the state shared by all the commands
+type environment struct {
+	client *amazonpa.Client
+	config amazonpa.Config
+	format string
+	stdout io.Writer
+}
+
+// commonFlags are the flags accepted by every command
+type commonFlags struct {
+	config string
+	region string
+	tag    string
+	format string
+}
+
+func (common *commonFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&common.config, "config", "", "path of the JSON config file")
+	flags.StringVar(&common.region, "region", "", "marketplace region (e.g. US, DE, JP)")
+	flags.StringVar(&common.tag, "tag", "", "associate tag")
+	flags.StringVar(&common.format, "format", "table", "output format: table, json or xml")
+}
+
+func (common *commonFlags) environment(stdout io.Writer) (*environment, error) {
+	path, explicit := common.config, common.config != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+
+	config, err := loadConfig(path, explicit)
+	if err != nil {
+		return nil, err
+	}
+
+	if common.region != "" {
+		config.Region = strings.ToUpper(common.region)
+	}
+	if common.tag != "" {
+		config.AssociateTag = common.tag
+	}
+
+	if err := checkConfig(config); err != nil {
+		return nil, err
+	}
+
+	switch common.format {
+	case "table", "json", "xml":
+	default:
+		return nil, fmt.Errorf("unknown output format %q", common.format)
+	}
+
+	return &environment{
+		client: amazonpa.NewClient(config),
+		config: config,
+		format: common.format,
+		stdout: stdout,
+	}, nil
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "amazonpa:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	commands := map[string]func() command{
+		"lookup": lookupCommand,
+		"search": searchCommand,
+		"browse": browseCommand,
+		"sign":   signCommand,
+	}
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("missing command")
+	}
+
+	newCommand, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	cmd := newCommand()
+	cmd.flags.SetOutput(stderr)
+
+	var common commonFlags
+	common.register(cmd.flags)
+
+	if err := cmd.flags.Parse(args[1:]); err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	env, err := common.environment(stdout)
+	if err != nil {
+		return err
+	}
+
+	return cmd.run(env, cmd.flags.Args())
+}
+
+// splitList splits a comma separated flag value
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// responseGroups parses a comma separated list of response groups
+func responseGroups(value string) []amazonpa.ResponseGroup {
+	var groups []amazonpa.ResponseGroup
+	for _, group := range splitList(value) {
+		groups = append(groups, amazonpa.ResponseGroup(group))
+	}
+
+	return groups
+}
+
+// boolFlag sets an optional boolean query field
+type boolFlag struct {
+	target **bool
+}
+
+func (f boolFlag) String() string {
+	if f.target == nil || *f.target == nil {
+		return ""
+	}
+
+	return strconv.FormatBool(**f.target)
+}
+
+func (f boolFlag) Set(value string) error {
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*f.target = amazonpa.Bool(v)
+
+	return nil
+}
+
+func (f boolFlag) IsBoolFlag() bool {
+	return true
+}
+
+// intFlag sets an optional integer query field
+type intFlag struct {
+	target **int
+}
+
+func (f intFlag) String() string {
+	if f.target == nil || *f.target == nil {
+		return ""
+	}
+
+	return strconv.Itoa(**f.target)
+}
+
+func (f intFlag) Set(value string) error {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*f.target = amazonpa.Int(v)
+
+	return nil
+}
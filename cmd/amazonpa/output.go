@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mattbit/amazonpa"
+)
+
+// printRaw performs the request and prints the XML returned by the API
+func (env *environment) printRaw(operation string, parameters map[string]string) error {
+	request := env.client.NewRequest(operation)
+	for key, value := range parameters {
+		request.SetParameter(key, value)
+	}
+
+	// Error responses are printed as well, as they are useful for debugging
+	contents, err := env.client.ProcessRequest(request)
+	if contents != nil {
+		fmt.Fprintln(env.stdout, string(contents))
+	}
+
+	return err
+}
+
+// printJSON prints the decoded response as indented JSON
+func (env *environment) printJSON(response interface{}) error {
+	encoder := json.NewEncoder(env.stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(response)
+}
+
+// printItems prints a table with a row per item
+func (env *environment) printItems(items []amazonpa.Item) error {
+	table := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ASIN\tTITLE\tPRICE\tSALES RANK\tURL")
+
+	for _, item := range items {
+		var title string
+		if item.ItemAttributes != nil {
+			title = item.ItemAttributes.Title
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%s\n", item.ASIN, title, itemPrice(item), item.SalesRank, item.DetailPageURL)
+	}
+
+	return table.Flush()
+}
+
+// printBrowseNode prints a browse node with its ancestors and top items
+func (env *environment) printBrowseNode(node amazonpa.BrowseNode) error {
+	table := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(table, "ID\t%s\n", node.BrowseNodeID)
+	fmt.Fprintf(table, "NAME\t%s\n", node.Name)
+
+	for ancestors := node.Ancestors.BrowseNode; len(ancestors) > 0; ancestors = ancestors[0].Ancestors.BrowseNode {
+		fmt.Fprintf(table, "ANCESTOR\t%s\t%s\n", ancestors[0].BrowseNodeID, ancestors[0].Name)
+	}
+
+	for _, set := range node.TopItemSets {
+		for _, item := range set.TopItems {
+			fmt.Fprintf(table, "%s\t%s\t%s\n", strings.ToUpper(set.Type), item.ASIN, item.Title)
+		}
+	}
+
+	// The TopItemSets already include the top sellers when returned
+	if len(node.TopItemSets) == 0 {
+		for _, seller := range node.TopSellers.TopSeller {
+			fmt.Fprintf(table, "TOP SELLER\t%s\t%s\n", seller.ASIN, seller.Title)
+		}
+	}
+
+	return table.Flush()
+}
+
+// itemPrice returns the most relevant formatted price of the item
+func itemPrice(item amazonpa.Item) string {
+	if item.OfferSummary.LowestNewPrice.FormattedPrice != "" {
+		return item.OfferSummary.LowestNewPrice.FormattedPrice
+	}
+
+	if item.ItemAttributes != nil {
+		return item.ItemAttributes.ListPrice.FormattedPrice
+	}
+
+	return ""
+}
@@ -0,0 +1,72 @@
+package amazonpa
+
+import "context"
+
+// BrowseNodeTree is a browse node with its descendants
+type BrowseNodeTree struct {
+	BrowseNodeID   string            `json:"browseNodeId"`
+	Name           string            `json:"name"`
+	IsCategoryRoot bool              `json:"isCategoryRoot,omitempty"`
+	Children       []*BrowseNodeTree `json:"children,omitempty"`
+}
+
+// CrawlBrowseNodes builds the tree of the browse nodes descending from
+// rootID, up to depth levels below the root. The nodes are visited
+// breadth-first with a BrowseNodeLookup request each, subject to the rate
+// limit of the client, and nodes reachable from several parents are only
+// included once. If the crawl stops early, the partial tree is returned
+// along with the error.
+func (client Client) CrawlBrowseNodes(ctx context.Context, rootID string, depth int) (*BrowseNodeTree, error) {
+	type pending struct {
+		tree  *BrowseNodeTree
+		level int
+	}
+
+	root := &BrowseNodeTree{BrowseNodeID: rootID}
+	queue := []pending{{root, 0}}
+	visited := map[string]bool{rootID: true}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		query := BrowseNodeLookupQuery{
+			BrowseNodeID:   current.tree.BrowseNodeID,
+			ResponseGroups: []ResponseGroup{ResponseGroupBrowseNodeInfo},
+		}
+
+		response, err := client.BrowseNodeLookupContext(ctx, query)
+		if err != nil {
+			return root, err
+		}
+
+		node := response.BrowseNodes.BrowseNode
+		current.tree.Name = node.Name
+		current.tree.IsCategoryRoot = node.IsCategoryRoot
+
+		if current.level >= depth {
+			continue
+		}
+
+		for _, child := range node.Children.BrowseNode {
+			if visited[child.BrowseNodeID] {
+				continue
+			}
+			visited[child.BrowseNodeID] = true
+
+			tree := &BrowseNodeTree{
+				BrowseNodeID:   child.BrowseNodeID,
+				Name:           child.Name,
+				IsCategoryRoot: child.IsCategoryRoot,
+			}
+			current.tree.Children = append(current.tree.Children, tree)
+
+			// The children of the deepest level are not needed
+			if current.level+1 < depth {
+				queue = append(queue, pending{tree, current.level + 1})
+			}
+		}
+	}
+
+	return root, nil
+}
@@ -0,0 +1,51 @@
+package amazonpa
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCrawlBrowseNodes(t *testing.T) {
+	children := map[string][]string{
+		"1": {"2", "3"},
+		"2": {"4", "3"},
+		"3": {"5"},
+	}
+
+	var lookups []string
+	client := newFuncClient(func(request *http.Request) (*http.Response, error) {
+		id := request.URL.Query().Get("BrowseNodeId")
+		lookups = append(lookups, id)
+
+		var body strings.Builder
+		fmt.Fprintf(&body, "<BrowseNodeLookupResponse><BrowseNodes><Request><IsValid>True</IsValid></Request>")
+		fmt.Fprintf(&body, "<BrowseNode><BrowseNodeId>%s</BrowseNodeId><Name>Node %s</Name><Children>", id, id)
+		for _, child := range children[id] {
+			fmt.Fprintf(&body, "<BrowseNode><BrowseNodeId>%s</BrowseNodeId><Name>Node %s</Name></BrowseNode>", child, child)
+		}
+		fmt.Fprintf(&body, "</Children></BrowseNode></BrowseNodes></BrowseNodeLookupResponse>")
+
+		return xmlResponse(http.StatusOK, body.String()), nil
+	})
+
+	tree, err := client.CrawlBrowseNodes(context.Background(), "1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualStr(t, strings.Join(lookups, ","), "1,2,3", "Bad lookups")
+	assertEqualStr(t, tree.Name, "Node 1", "Bad root name")
+	assertEqualInt(t, len(tree.Children), 2, "Bad number of root children")
+	assertEqualInt(t, len(tree.Children[0].Children), 1, "Duplicated nodes must be skipped")
+	assertEqualStr(t, tree.Children[0].Children[0].BrowseNodeID, "4", "Bad grandchild")
+	assertEqualStr(t, tree.Children[1].Children[0].Name, "Node 5", "Bad grandchild name")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.CrawlBrowseNodes(ctx, "1", 2); err != context.Canceled {
+		t.Errorf("Crawl must stop when the context is done, got %v", err)
+	}
+}
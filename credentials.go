@@ -0,0 +1,207 @@
+package amazonpa
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials are the keys used to sign the requests. If AssociateTag is
+// set, it replaces the associate tag of the Config in the signed requests.
+type Credentials struct {
+	AccessKey    string
+	AccessSecret string
+	AssociateTag string
+}
+
+// CredentialsProvider provides the credentials used to sign each request
+type CredentialsProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// StaticCredentials provides fixed credentials
+type StaticCredentials Credentials
+
+// Credentials returns the fixed credentials
+func (static StaticCredentials) Credentials() (Credentials, error) {
+	return Credentials(static), nil
+}
+
+// EnvCredentials reads the credentials from the AMAZONPA_ACCESS_KEY and
+// AMAZONPA_ACCESS_SECRET environment variables, falling back to
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+type EnvCredentials struct{}
+
+// Credentials returns the credentials found in the environment
+func (EnvCredentials) Credentials() (Credentials, error) {
+	credentials := Credentials{
+		AccessKey:    os.Getenv("AMAZONPA_ACCESS_KEY"),
+		AccessSecret: os.Getenv("AMAZONPA_ACCESS_SECRET"),
+	}
+
+	if credentials.AccessKey == "" && credentials.AccessSecret == "" {
+		credentials.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		credentials.AccessSecret = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+
+	if credentials.AccessKey == "" || credentials.AccessSecret == "" {
+		return credentials, errors.New("amazonpa: no credentials in the environment")
+	}
+
+	return credentials, nil
+}
+
+// FileCredentials reads the credentials from a file each time they are
+// requested. Files with the .json extension contain a JSON object with
+// the access_key, access_secret and optional associate_tag fields; other
+// files are INI files with the same keys (or aws_access_key_id and
+// aws_secret_access_key) in the Profile section, "default" if empty.
+type FileCredentials struct {
+	Path    string
+	Profile string
+}
+
+// Credentials reads the credentials from the file
+func (file FileCredentials) Credentials() (Credentials, error) {
+	data, err := ioutil.ReadFile(file.Path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("amazonpa: cannot read credentials: %v", err)
+	}
+
+	var credentials Credentials
+	if strings.EqualFold(filepath.Ext(file.Path), ".json") {
+		credentials, err = parseJSONCredentials(data)
+	} else {
+		credentials, err = parseINICredentials(data, file.Profile)
+	}
+
+	if err != nil {
+		return credentials, fmt.Errorf("amazonpa: cannot parse credentials in %s: %v", file.Path, err)
+	}
+
+	if credentials.AccessKey == "" || credentials.AccessSecret == "" {
+		return credentials, fmt.Errorf("amazonpa: incomplete credentials in %s", file.Path)
+	}
+
+	return credentials, nil
+}
+
+func parseJSONCredentials(data []byte) (Credentials, error) {
+	var file struct {
+		AccessKey    string `json:"access_key"`
+		AccessSecret string `json:"access_secret"`
+		AssociateTag string `json:"associate_tag"`
+	}
+
+	err := json.Unmarshal(data, &file)
+
+	return Credentials{file.AccessKey, file.AccessSecret, file.AssociateTag}, err
+}
+
+func parseINICredentials(data []byte, profile string) (Credentials, error) {
+	if profile == "" {
+		profile = "default"
+	}
+
+	var credentials Credentials
+	section := "default"
+	found := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '[' && line[len(line)-1] == ']':
+			section = strings.TrimSpace(strings.TrimPrefix(line[1:len(line)-1], "profile "))
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return credentials, fmt.Errorf("invalid line %q", line)
+		}
+
+		if section != profile {
+			continue
+		}
+		found = true
+
+		switch key, value := strings.ToLower(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1]); key {
+		case "access_key", "aws_access_key_id":
+			credentials.AccessKey = value
+		case "access_secret", "aws_secret_access_key":
+			credentials.AccessSecret = value
+		case "associate_tag":
+			credentials.AssociateTag = value
+		}
+	}
+
+	if !found {
+		return credentials, fmt.Errorf("profile %s not found", profile)
+	}
+
+	return credentials, scanner.Err()
+}
+
+// RotatingCredentials reads the credentials from a file like
+// FileCredentials, caching them until the file changes. Rotating the keys
+// only requires to rewrite the file. If the new file cannot be read, the
+// previous credentials are kept.
+type RotatingCredentials struct {
+	file        FileCredentials
+	mutex       sync.Mutex
+	modTime     time.Time
+	size        int64
+	credentials Credentials
+	loaded      bool
+}
+
+// NewRotatingCredentials returns a provider reading the credentials of
+// the profile from the file at path
+func NewRotatingCredentials(path, profile string) *RotatingCredentials {
+	return &RotatingCredentials{file: FileCredentials{Path: path, Profile: profile}}
+}
+
+// Credentials returns the credentials, reading the file again if changed
+func (rotating *RotatingCredentials) Credentials() (Credentials, error) {
+	rotating.mutex.Lock()
+	defer rotating.mutex.Unlock()
+
+	info, err := os.Stat(rotating.file.Path)
+	if err != nil {
+		if rotating.loaded {
+			return rotating.credentials, nil
+		}
+		return Credentials{}, fmt.Errorf("amazonpa: cannot read credentials: %v", err)
+	}
+
+	if rotating.loaded && info.ModTime().Equal(rotating.modTime) && info.Size() == rotating.size {
+		return rotating.credentials, nil
+	}
+
+	credentials, err := rotating.file.Credentials()
+	if err != nil {
+		if rotating.loaded {
+			return rotating.credentials, nil
+		}
+		return credentials, err
+	}
+
+	rotating.credentials = credentials
+	rotating.modTime = info.ModTime()
+	rotating.size = info.Size()
+	rotating.loaded = true
+
+	return credentials, nil
+}
@@ -0,0 +1,119 @@
+package amazonpa
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileCredentials(t *testing.T) {
+	dir := t.TempDir()
+
+	iniPath := filepath.Join(dir, "credentials")
+	ioutil.WriteFile(iniPath, []byte("# keys\n[default]\naccess_key = AKDEFAULT\naccess_secret = secret\n\n[profile shop]\naws_access_key_id=AKSHOP\naws_secret_access_key=shopsecret\n"), 0600)
+
+	credentials, err := FileCredentials{Path: iniPath}.Credentials()
+	if err != nil || credentials != (Credentials{AccessKey: "AKDEFAULT", AccessSecret: "secret"}) {
+		t.Errorf("Bad default credentials %+v (%v)", credentials, err)
+	}
+
+	credentials, err = FileCredentials{Path: iniPath, Profile: "shop"}.Credentials()
+	if err != nil || credentials != (Credentials{AccessKey: "AKSHOP", AccessSecret: "shopsecret"}) {
+		t.Errorf("Bad profile credentials %+v (%v)", credentials, err)
+	}
+
+	if _, err := (FileCredentials{Path: iniPath, Profile: "missing"}).Credentials(); err == nil {
+		t.Error("Missing profiles must fail")
+	}
+
+	jsonPath := filepath.Join(dir, "credentials.json")
+	ioutil.WriteFile(jsonPath, []byte(`{"access_key": "AKJSON", "access_secret": "jsonsecret", "associate_tag": "json-20"}`), 0600)
+
+	credentials, err = FileCredentials{Path: jsonPath}.Credentials()
+	if err != nil || credentials != (Credentials{AccessKey: "AKJSON", AccessSecret: "jsonsecret", AssociateTag: "json-20"}) {
+		t.Errorf("Bad JSON credentials %+v (%v)", credentials, err)
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("AMAZONPA_ACCESS_KEY", "")
+	t.Setenv("AMAZONPA_ACCESS_SECRET", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKAWS")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "awssecret")
+
+	credentials, err := EnvCredentials{}.Credentials()
+	if err != nil || credentials != (Credentials{AccessKey: "AKAWS", AccessSecret: "awssecret"}) {
+		t.Errorf("Bad environment credentials %+v (%v)", credentials, err)
+	}
+
+	t.Setenv("AMAZONPA_ACCESS_KEY", "AKENV")
+	t.Setenv("AMAZONPA_ACCESS_SECRET", "envsecret")
+
+	credentials, _ = EnvCredentials{}.Credentials()
+	assertEqualStr(t, credentials.AccessKey, "AKENV", "AMAZONPA variables must take precedence")
+}
+
+func TestRotatingCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	ioutil.WriteFile(path, []byte(`{"access_key": "AKOLD", "access_secret": "old"}`), 0600)
+
+	client := newTestClient()
+	client.SetCredentialsProvider(NewRotatingCredentials(path, ""))
+
+	request := client.NewRequest("ItemLookup")
+	if err := client.TrySignRequest(request); err != nil {
+		t.Fatal(err)
+	}
+	assertEqualStr(t, request.Parameters()["AWSAccessKeyId"], "AKOLD", "Bad signing key")
+	oldSignature := request.signature
+
+	ioutil.WriteFile(path, []byte(`{"access_key": "AKNEW", "access_secret": "new-secret"}`), 0600)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(path, later, later)
+
+	request = client.NewRequest("ItemLookup")
+	request.SetParameter("Timestamp", "2014-08-18T12:00:00Z")
+	client.SignRequest(request)
+	assertEqualStr(t, request.Parameters()["AWSAccessKeyId"], "AKNEW", "Rotated keys must be used")
+	if request.signature == oldSignature {
+		t.Error("Rotated secret must be used")
+	}
+
+	// A broken file keeps the previous credentials
+	ioutil.WriteFile(path, []byte(`{`), 0600)
+	os.Chtimes(path, later.Add(time.Minute), later.Add(time.Minute))
+
+	if _, err := NewRotatingCredentials(path, "").Credentials(); err == nil {
+		t.Error("Broken files must fail when never loaded")
+	}
+
+	credentials, err := client.credentials.Credentials()
+	if err != nil || credentials.AccessKey != "AKNEW" {
+		t.Errorf("Previous credentials must be kept, got %+v (%v)", credentials, err)
+	}
+}
+
+// failingCredentials is a provider that cannot return credentials
+type failingCredentials struct{}
+
+func (failingCredentials) Credentials() (Credentials, error) {
+	return Credentials{}, errors.New("vault is sealed")
+}
+
+func TestSignRequestProviderError(t *testing.T) {
+	client := newTestClient()
+	client.SetCredentialsProvider(failingCredentials{})
+
+	request := client.NewRequest("ItemLookup")
+	if err := client.TrySignRequest(request); err == nil || err.Error() != "vault is sealed" {
+		t.Errorf("Expected the provider error, got %v", err)
+	}
+
+	client.SignRequest(request)
+	if _, err := request.SignedURL(); err == nil {
+		t.Error("Request must be left unsigned")
+	}
+}
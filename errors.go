@@ -0,0 +1,129 @@
+package amazonpa
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// excerptLength is the maximum length of the body excerpt of a DecodeError
+const excerptLength = 256
+
+// DecodeError is returned when the server response cannot be decoded,
+// for example because the API returned an HTML page or a truncated body
+type DecodeError struct {
+	Operation   string
+	ContentType string
+	Excerpt     string
+	Err         error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("amazonpa: cannot decode %s response: %v (body: %q)", e.Operation, e.Err, e.Excerpt)
+}
+
+// Unwrap returns the underlying error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// APIError is an error reported by the API in an error response, such as
+// ItemLookupErrorResponse
+type APIError struct {
+	Operation  string
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("amazonpa: %s failed with %s: %s", e.Operation, e.Code, e.Message)
+}
+
+// errorResponse is the body of an error response, whose root element is
+// named after the operation (e.g. ItemLookupErrorResponse)
+type errorResponse struct {
+	Error struct {
+		Code    string
+		Message string
+	}
+	RequestID string `xml:"RequestId"`
+}
+
+func newDecodeError(operation, contentType string, body []byte, err error) *DecodeError {
+	excerpt := body
+	if len(excerpt) > excerptLength {
+		excerpt = excerpt[:excerptLength]
+	}
+
+	return &DecodeError{
+		Operation:   operation,
+		ContentType: contentType,
+		Excerpt:     strings.ToValidUTF8(string(excerpt), ""),
+		Err:         err,
+	}
+}
+
+// isXMLContentType reports whether the content type may carry XML
+func isXMLContentType(contentType string) bool {
+	return contentType == "" || strings.Contains(strings.ToLower(contentType), "xml")
+}
+
+// checkResponse verifies that the body is an XML document and converts
+// error responses into an *APIError
+func checkResponse(operation string, statusCode int, contentType string, body []byte) error {
+	if !isXMLContentType(contentType) {
+		return newDecodeError(operation, contentType, body, fmt.Errorf("unexpected content type %q", contentType))
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+
+	for {
+		token, err := decoder.Token()
+
+		if err == io.EOF {
+			return newDecodeError(operation, contentType, body, io.ErrUnexpectedEOF)
+		}
+
+		if err != nil {
+			return newDecodeError(operation, contentType, body, err)
+		}
+
+		if root, ok := token.(xml.StartElement); ok {
+			if !strings.HasSuffix(root.Name.Local, "ErrorResponse") {
+				return nil
+			}
+
+			return decodeErrorResponse(operation, statusCode, contentType, body, decoder, root)
+		}
+	}
+}
+
+// decodeErrorResponse decodes the error response starting at root
+func decodeErrorResponse(operation string, statusCode int, contentType string, body []byte, decoder *xml.Decoder, root xml.StartElement) error {
+	var response errorResponse
+
+	if err := decoder.DecodeElement(&response, &root); err != nil {
+		return newDecodeError(operation, contentType, body, err)
+	}
+
+	return &APIError{
+		Operation:  operation,
+		StatusCode: statusCode,
+		Code:       response.Error.Code,
+		Message:    response.Error.Message,
+		RequestID:  response.RequestID,
+	}
+}
+
+// decodeResponse unmarshals the body of a response of the operation
+func decodeResponse(operation string, body []byte, response interface{}) error {
+	if err := xml.Unmarshal(body, response); err != nil {
+		return newDecodeError(operation, "", body, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package amazonpa
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrorResponse(t *testing.T) {
+	client := newFixtureClientWithStatus("testdata/itemlookup_error_response.xml", http.StatusServiceUnavailable, "text/xml;charset=UTF-8")
+
+	_, err := client.ItemLookup(ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}})
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	assertEqualStr(t, apiError.Operation, "ItemLookup", "Bad Operation")
+	assertEqualStr(t, apiError.Code, "RequestThrottled", "Bad Code")
+	assertEqualStr(t, apiError.RequestID, "9a1b6c3e-4b0f-4a6e-9d7e-1f2a8b3c4d5e", "Bad RequestID")
+	assertEqualInt(t, apiError.StatusCode, http.StatusServiceUnavailable, "Bad StatusCode")
+
+	err = client.ItemLookupStream(ItemLookupQuery{}, func(item Item) error { return nil })
+	if !errors.As(err, &apiError) || apiError.Code != "RequestThrottled" {
+		t.Errorf("Expected an APIError from the stream, got %v", err)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	client := newFixtureClientWithStatus("testdata/itemlookup_response.xml", http.StatusOK, "text/html")
+
+	_, err := client.ItemSearch(ItemSearchQuery{SearchIndex: "All", Keywords: "mouse"})
+
+	var decodeError *DecodeError
+	if !errors.As(err, &decodeError) {
+		t.Fatalf("Expected a DecodeError, got %v", err)
+	}
+	assertEqualStr(t, decodeError.Operation, "ItemSearch", "Bad Operation")
+	assertEqualStr(t, decodeError.ContentType, "text/html", "Bad ContentType")
+	if !strings.HasPrefix(decodeError.Excerpt, "<?xml") || len(decodeError.Excerpt) > excerptLength {
+		t.Error("Bad Excerpt")
+	}
+
+	err = decodeResponse("ItemLookup", []byte("<ItemLookupResponse><Items>"), &ItemLookupResponse{})
+	if !errors.As(err, &decodeError) || decodeError.Excerpt != "<ItemLookupResponse><Items>" {
+		t.Errorf("Expected a DecodeError for a truncated body, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package export writes items returned by the API as CSV or TSV tables,
+// suitable for spreadsheets.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mattbit/amazonpa"
+)
+
+// Column describes a column of the exported table
+type Column struct {
+	Name  string
+	Value func(item amazonpa.Item) string
+}
+
+// Available columns
+var (
+	ASIN = Column{"ASIN", func(item amazonpa.Item) string {
+		return item.ASIN
+	}}
+	Title = Column{"Title", func(item amazonpa.Item) string {
+		return attributes(item).Title
+	}}
+	Brand = Column{"Brand", func(item amazonpa.Item) string {
+		return attributes(item).Brand
+	}}
+	Manufacturer = Column{"Manufacturer", func(item amazonpa.Item) string {
+		return attributes(item).Manufacturer
+	}}
+	EAN = Column{"EAN", func(item amazonpa.Item) string {
+		return attributes(item).EAN
+	}}
+	ListPrice = Column{"ListPrice", func(item amazonpa.Item) string {
+		return FormatPrice(attributes(item).ListPrice)
+	}}
+	ListPriceCurrency = Column{"ListPriceCurrency", func(item amazonpa.Item) string {
+		return attributes(item).ListPrice.CurrencyCode
+	}}
+	LowestNewPrice = Column{"LowestNewPrice", func(item amazonpa.Item) string {
+		return FormatPrice(item.OfferSummary.LowestNewPrice)
+	}}
+	LowestNewPriceCurrency = Column{"LowestNewPriceCurrency", func(item amazonpa.Item) string {
+		return item.OfferSummary.LowestNewPrice.CurrencyCode
+	}}
+	SalesRank = Column{"SalesRank", func(item amazonpa.Item) string {
+		if item.SalesRank == 0 {
+			return ""
+		}
+		return strconv.Itoa(item.SalesRank)
+	}}
+	DetailPageURL = Column{"DetailPageURL", func(item amazonpa.Item) string {
+		return item.DetailPageURL
+	}}
+	ImageURL = Column{"ImageURL", func(item amazonpa.Item) string {
+		for _, image := range []*amazonpa.Image{item.LargeImage, item.MediumImage, item.SmallImage} {
+			if image != nil {
+				return image.URL
+			}
+		}
+		return ""
+	}}
+)
+
+// DefaultColumns are the columns exported when none are specified
+var DefaultColumns = []Column{ASIN, Title, Brand, ListPrice, ListPriceCurrency, LowestNewPrice, LowestNewPriceCurrency, SalesRank, DetailPageURL, ImageURL}
+
+var columnsByName = map[string]Column{}
+
+func init() {
+	for _, column := range append(DefaultColumns, Manufacturer, EAN) {
+		columnsByName[strings.ToLower(column.Name)] = column
+	}
+}
+
+// ParseColumns returns the columns named in a comma separated list
+func ParseColumns(spec string) ([]Column, error) {
+	var columns []Column
+
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		column, ok := columnsByName[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("export: unknown column %q", name)
+		}
+		columns = append(columns, column)
+	}
+
+	if len(columns) == 0 {
+		return DefaultColumns, nil
+	}
+
+	return columns, nil
+}
+
+// zeroDecimalCurrencies are the currencies without a minor unit
+var zeroDecimalCurrencies = map[string]bool{
+	"JPY": true,
+	"KRW": true,
+}
+
+// FormatPrice formats a price as a plain decimal number in its currency
+// (e.g. 18500 EUR cents become "185.00"). Missing prices are empty.
+func FormatPrice(price amazonpa.Price) string {
+	if price.CurrencyCode == "" && price.Amount == 0 {
+		return ""
+	}
+
+	if zeroDecimalCurrencies[price.CurrencyCode] {
+		return strconv.FormatUint(uint64(price.Amount), 10)
+	}
+
+	return fmt.Sprintf("%d.%02d", price.Amount/100, price.Amount%100)
+}
+
+func attributes(item amazonpa.Item) amazonpa.ItemAttributes {
+	if item.ItemAttributes == nil {
+		return amazonpa.ItemAttributes{}
+	}
+
+	return *item.ItemAttributes
+}
+
+// Writer writes items as rows of a table
+type Writer struct {
+	csv     *csv.Writer
+	columns []Column
+	header  bool
+}
+
+// NewCSVWriter returns a Writer producing comma separated values
+func NewCSVWriter(w io.Writer, columns []Column) *Writer {
+	return &Writer{csv: csv.NewWriter(w), columns: columns}
+}
+
+// NewTSVWriter returns a Writer producing tab separated values
+func NewTSVWriter(w io.Writer, columns []Column) *Writer {
+	writer := NewCSVWriter(w, columns)
+	writer.csv.Comma = '\t'
+
+	return writer
+}
+
+// Write writes a row per item, preceded by the header on the first call
+func (writer *Writer) Write(items []amazonpa.Item) error {
+	if !writer.header {
+		names := make([]string, len(writer.columns))
+		for i, column := range writer.columns {
+			names[i] = column.Name
+		}
+
+		if err := writer.csv.Write(names); err != nil {
+			return err
+		}
+		writer.header = true
+	}
+
+	for _, item := range items {
+		row := make([]string, len(writer.columns))
+		for i, column := range writer.columns {
+			row[i] = column.Value(item)
+		}
+
+		if err := writer.csv.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush writes any buffered data to the underlying writer
+func (writer *Writer) Flush() error {
+	writer.csv.Flush()
+
+	return writer.csv.Error()
+}
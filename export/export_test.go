@@ -0,0 +1,65 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattbit/amazonpa"
+)
+
+func TestFormatPrice(t *testing.T) {
+	prices := map[string]amazonpa.Price{
+		"185.00": {Amount: 18500, CurrencyCode: "EUR"},
+		"0.99":   {Amount: 99, CurrencyCode: "USD"},
+		"1980":   {Amount: 1980, CurrencyCode: "JPY"},
+		"":       {},
+	}
+
+	for expected, price := range prices {
+		if formatted := FormatPrice(price); formatted != expected {
+			t.Errorf("Price %v formatted as %q instead of %q", price, formatted, expected)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	columns, err := ParseColumns("asin, Title,ListPrice,SalesRank")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []amazonpa.Item{
+		{
+			ASIN:           "B003TGG2EA",
+			SalesRank:      214,
+			ItemAttributes: &amazonpa.ItemAttributes{Title: "Grohe, Cosmopolitan", ListPrice: amazonpa.Price{Amount: 18500, CurrencyCode: "EUR"}},
+		},
+		{ASIN: "B000000000"},
+	}
+
+	var buffer bytes.Buffer
+	writer := NewCSVWriter(&buffer, columns)
+	writer.Write(items[:1])
+	writer.Write(items[1:])
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "ASIN,Title,ListPrice,SalesRank\nB003TGG2EA,\"Grohe, Cosmopolitan\",185.00,214\nB000000000,,,\n"
+	if buffer.String() != expected {
+		t.Errorf("Bad CSV output:\n%s", buffer.String())
+	}
+
+	buffer.Reset()
+	writer = NewTSVWriter(&buffer, columns[:2])
+	writer.Write(items[:1])
+	writer.Flush()
+
+	if buffer.String() != "ASIN\tTitle\nB003TGG2EA\tGrohe, Cosmopolitan\n" {
+		t.Errorf("Bad TSV output:\n%s", buffer.String())
+	}
+
+	if _, err := ParseColumns("ASIN,Colour"); err == nil {
+		t.Error("Unknown columns must be rejected")
+	}
+}
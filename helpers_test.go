@@ -0,0 +1,78 @@
+package amazonpa
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// fixtureTransport answers every request with the content of a file
+type fixtureTransport struct {
+	path        string
+	statusCode  int
+	contentType string
+}
+
+func (fixture fixtureTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	file, err := os.Open(fixture.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: fixture.statusCode,
+		Header:     http.Header{"Content-Type": {fixture.contentType}},
+		Body:       file,
+		Request:    request,
+	}, nil
+}
+
+func newFixtureClient(path string) *Client {
+	return newFixtureClientWithStatus(path, http.StatusOK, "text/xml;charset=UTF-8")
+}
+
+func newFixtureClientWithStatus(path string, statusCode int, contentType string) *Client {
+	client := newTestClient()
+	client.SetHTTPClient(&http.Client{Transport: fixtureTransport{path, statusCode, contentType}})
+
+	return client
+}
+
+// roundTripFunc allows to use a function as http.RoundTripper
+type roundTripFunc func(request *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// newFuncClient returns a test client whose requests are answered by f
+func newFuncClient(f roundTripFunc) *Client {
+	client := newTestClient()
+	client.SetHTTPClient(&http.Client{Transport: f})
+
+	return client
+}
+
+// xmlResponse returns a response with an XML body
+func xmlResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": {"text/xml"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// itemLookupXML returns a valid ItemLookup response with the given items
+func itemLookupXML(asins ...string) string {
+	var body strings.Builder
+
+	body.WriteString("<ItemLookupResponse><Items><Request><IsValid>True</IsValid></Request>")
+	for _, asin := range asins {
+		fmt.Fprintf(&body, "<Item><ASIN>%s</ASIN></Item>", asin)
+	}
+	body.WriteString("</Items></ItemLookupResponse>")
+
+	return body.String()
+}
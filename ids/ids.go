@@ -0,0 +1,196 @@
+// Package ids validates, converts and detects the product identifiers
+// accepted by ItemLookup: ASIN, ISBN-10, ISBN-13, EAN, UPC and JAN.
+package ids
+
+import (
+	"errors"
+	"strings"
+)
+
+// Type is the type of a product identifier
+type Type string
+
+// Identifier types
+const (
+	Unknown Type = ""
+	ASIN    Type = "ASIN"
+	ISBN10  Type = "ISBN-10"
+	ISBN13  Type = "ISBN-13"
+	EAN     Type = "EAN"
+	UPC     Type = "UPC"
+	JAN     Type = "JAN"
+)
+
+// ErrInvalid is returned when converting an invalid identifier
+var ErrInvalid = errors.New("ids: invalid identifier")
+
+// Normalize removes spaces and hyphens from an identifier and converts it
+// to upper case
+func Normalize(raw string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw)))
+}
+
+// Detect returns the type of an identifier and its normalized form.
+// ISBN-10 numbers are reported as ISBN10, although they are also the ASIN
+// of the corresponding book.
+func Detect(raw string) (Type, string) {
+	id := Normalize(raw)
+
+	switch {
+	case ValidISBN10(id):
+		return ISBN10, id
+	case ValidASIN(id):
+		return ASIN, id
+	case ValidUPC(id):
+		return UPC, id
+	case ValidISBN13(id):
+		return ISBN13, id
+	case ValidJAN(id):
+		return JAN, id
+	case ValidEAN(id):
+		return EAN, id
+	}
+
+	return Unknown, id
+}
+
+func isDigits(id string) bool {
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return id != ""
+}
+
+// gtinCheckDigit computes the check digit of the GTIN (EAN, UPC) payload
+func gtinCheckDigit(payload string) byte {
+	sum := 0
+	for i := 0; i < len(payload); i++ {
+		digit := int(payload[len(payload)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func validGTIN(id string, length int) bool {
+	return len(id) == length && isDigits(id) && gtinCheckDigit(id[:length-1]) == id[length-1]
+}
+
+// isbn10CheckDigit computes the check digit of the first 9 digits of an ISBN-10
+func isbn10CheckDigit(payload string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(payload[i]-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+
+	return byte('0' + check)
+}
+
+// ValidISBN10 reports whether id is a valid ISBN-10
+func ValidISBN10(id string) bool {
+	return len(id) == 10 && isDigits(id[:9]) && isbn10CheckDigit(id) == id[9]
+}
+
+// ValidISBN13 reports whether id is a valid ISBN-13 (an EAN-13 in the
+// 978 or 979 Bookland prefixes)
+func ValidISBN13(id string) bool {
+	return validGTIN(id, 13) && (strings.HasPrefix(id, "978") || strings.HasPrefix(id, "979"))
+}
+
+// ValidEAN reports whether id is a valid EAN-13 or EAN-8
+func ValidEAN(id string) bool {
+	return validGTIN(id, 13) || validGTIN(id, 8)
+}
+
+// ValidUPC reports whether id is a valid UPC-A
+func ValidUPC(id string) bool {
+	return validGTIN(id, 12)
+}
+
+// ValidJAN reports whether id is a valid JAN, the EAN-13 used in Japan
+// with the 45 or 49 prefixes
+func ValidJAN(id string) bool {
+	return validGTIN(id, 13) && (strings.HasPrefix(id, "45") || strings.HasPrefix(id, "49"))
+}
+
+// ValidASIN reports whether id has the format of an ASIN: either an
+// ISBN-10 or ten upper case alphanumeric characters starting with B
+func ValidASIN(id string) bool {
+	if ValidISBN10(id) {
+		return true
+	}
+
+	if len(id) != 10 || id[0] != 'B' {
+		return false
+	}
+
+	for _, r := range id {
+		if !(r >= '0' && r <= '9' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ISBN10To13 converts an ISBN-10 to the equivalent ISBN-13
+func ISBN10To13(isbn string) (string, error) {
+	isbn = Normalize(isbn)
+	if !ValidISBN10(isbn) {
+		return "", ErrInvalid
+	}
+
+	payload := "978" + isbn[:9]
+
+	return payload + string(gtinCheckDigit(payload)), nil
+}
+
+// ISBN13To10 converts an ISBN-13 to the equivalent ISBN-10, which only
+// exists for the 978 prefix
+func ISBN13To10(isbn string) (string, error) {
+	isbn = Normalize(isbn)
+	if !ValidISBN13(isbn) || !strings.HasPrefix(isbn, "978") {
+		return "", ErrInvalid
+	}
+
+	payload := isbn[3:12]
+
+	return payload + string(isbn10CheckDigit(payload)), nil
+}
+
+// UPCToEAN converts a UPC-A to the equivalent EAN-13
+func UPCToEAN(upc string) (string, error) {
+	upc = Normalize(upc)
+	if !ValidUPC(upc) {
+		return "", ErrInvalid
+	}
+
+	return "0" + upc, nil
+}
+
+// EANToUPC converts an EAN-13 to the equivalent UPC-A, which only exists
+// for the EANs starting with 0
+func EANToUPC(ean string) (string, error) {
+	ean = Normalize(ean)
+	if !validGTIN(ean, 13) || ean[0] != '0' {
+		return "", ErrInvalid
+	}
+
+	return ean[1:], nil
+}
@@ -0,0 +1,56 @@
+package ids
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	cases := map[string]Type{
+		"0-306-40615-2":     ISBN10,
+		"080442957X":        ISBN10,
+		"B003TGG2EA":        ASIN,
+		"b003tgg2ea":        ASIN,
+		"978-0-306-40615-7": ISBN13,
+		"4005176874840":     EAN,
+		"4901234567894":     JAN,
+		"036000291452":      UPC,
+		"96385074":          EAN,
+		"4005176874841":     Unknown,
+		"0306406153":        Unknown,
+		"not an id":         Unknown,
+	}
+
+	for raw, expected := range cases {
+		if detected, _ := Detect(raw); detected != expected {
+			t.Errorf("Identifier %q detected as %q instead of %q", raw, detected, expected)
+		}
+	}
+
+	if _, id := Detect(" 978-0-306-40615-7 "); id != "9780306406157" {
+		t.Errorf("Bad normalized identifier %q", id)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	if isbn, err := ISBN10To13("0-306-40615-2"); err != nil || isbn != "9780306406157" {
+		t.Errorf("Bad ISBN-13 %q (%v)", isbn, err)
+	}
+
+	if isbn, err := ISBN13To10("9780804429573"); err != nil || isbn != "080442957X" {
+		t.Errorf("Bad ISBN-10 %q (%v)", isbn, err)
+	}
+
+	if _, err := ISBN13To10("9791234567896"); err != ErrInvalid {
+		t.Error("979 ISBN-13 have no ISBN-10")
+	}
+
+	if ean, err := UPCToEAN("036000291452"); err != nil || ean != "0036000291452" {
+		t.Errorf("Bad EAN %q (%v)", ean, err)
+	}
+
+	if upc, err := EANToUPC("0036000291452"); err != nil || upc != "036000291452" {
+		t.Errorf("Bad UPC %q (%v)", upc, err)
+	}
+
+	if _, err := EANToUPC("4005176874840"); err != ErrInvalid {
+		t.Error("EANs not starting with 0 have no UPC")
+	}
+}
@@ -0,0 +1,61 @@
+package amazonpa
+
+import "encoding/json"
+
+// ItemSchemaVersion is the version of the JSON representation of Item,
+// described by schema/item.v1.json. Field names are stable within a
+// version: renaming or removing a field requires a new version.
+const ItemSchemaVersion = "1"
+
+// The API wraps collections in an extra element (e.g. BrowseNodes>BrowseNode),
+// which is flattened to a plain array in the JSON representation.
+
+// MarshalJSON encodes the browse nodes as an array
+func (nodes BrowseNodes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nodes.BrowseNode)
+}
+
+// UnmarshalJSON decodes the browse nodes from an array
+func (nodes *BrowseNodes) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &nodes.BrowseNode)
+}
+
+// MarshalJSON encodes the top sellers as an array
+func (sellers TopSellers) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sellers.TopSeller)
+}
+
+// UnmarshalJSON decodes the top sellers from an array
+func (sellers *TopSellers) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &sellers.TopSeller)
+}
+
+// MarshalJSON encodes the new releases as an array
+func (releases NewReleases) MarshalJSON() ([]byte, error) {
+	return json.Marshal(releases.NewRelease)
+}
+
+// UnmarshalJSON decodes the new releases from an array
+func (releases *NewReleases) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &releases.NewRelease)
+}
+
+// MarshalJSON encodes the image sets as an array
+func (sets ImageSets) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sets.ImageSet)
+}
+
+// UnmarshalJSON decodes the image sets from an array
+func (sets *ImageSets) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &sets.ImageSet)
+}
+
+// MarshalJSON encodes the editorial review as a single object
+func (reviews EditorialReviews) MarshalJSON() ([]byte, error) {
+	return json.Marshal(reviews.EditorialReview)
+}
+
+// UnmarshalJSON decodes the editorial review from a single object
+func (reviews *EditorialReviews) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &reviews.EditorialReview)
+}
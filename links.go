@@ -0,0 +1,154 @@
+package amazonpa
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Links builds affiliate links to the Amazon store of a marketplace,
+// carrying an associate tag
+type Links struct {
+	region string
+	tag    string
+}
+
+// CartItem is an item added to the cart by an add-to-cart link
+type CartItem struct {
+	ASIN     string
+	Quantity int
+}
+
+// Links returns the link builder for the marketplace and the associate
+// tag of the client
+func (client Client) Links() Links {
+	return Links{region: client.config.Region, tag: client.config.AssociateTag}
+}
+
+// ForRegion returns a link builder for the marketplace of another region
+func (links Links) ForRegion(region string) Links {
+	links.region = region
+	return links
+}
+
+// WithTrackingID returns a link builder using a tracking ID, such as the
+// one of a campaign, instead of the default associate tag
+func (links Links) WithTrackingID(id string) Links {
+	links.tag = id
+	return links
+}
+
+// Tag returns the associate tag or tracking ID carried by the links
+func (links Links) Tag() string {
+	return links.tag
+}
+
+// host returns the host of the store, such as www.amazon.de, or an error
+// if the region has no store
+func (links Links) host() (string, error) {
+	endpoint, ok := Endpoints[links.region]
+	if !ok {
+		return "", fmt.Errorf("amazonpa: unknown region %q", links.region)
+	}
+
+	return "www." + strings.TrimPrefix(endpoint, "webservices."), nil
+}
+
+func (links Links) build(path string, query url.Values) (string, error) {
+	host, err := links.host()
+	if err != nil {
+		return "", err
+	}
+
+	link := url.URL{Scheme: "https", Host: host, Path: path, RawQuery: query.Encode()}
+	return link.String(), nil
+}
+
+// Product returns the link to the page of a product. The link builders
+// return an error if the region of the links is unknown.
+func (links Links) Product(asin string) (string, error) {
+	return links.build("/dp/"+url.PathEscape(asin)+"/", url.Values{"tag": {links.tag}})
+}
+
+// AddToCart returns a link adding the items to the cart of the visitor
+func (links Links) AddToCart(items ...CartItem) (string, error) {
+	query := url.Values{"AssociateTag": {links.tag}}
+
+	for i, item := range items {
+		quantity := item.Quantity
+		if quantity < 1 {
+			quantity = 1
+		}
+
+		n := strconv.Itoa(i + 1)
+		query.Set("ASIN."+n, item.ASIN)
+		query.Set("Quantity."+n, strconv.Itoa(quantity))
+	}
+
+	return links.build("/gp/aws/cart/add.html", query)
+}
+
+// Search returns the link to the search results of the keywords
+func (links Links) Search(keywords string) (string, error) {
+	return links.build("/s", url.Values{"k": {keywords}, "tag": {links.tag}})
+}
+
+// BrowseNode returns the link to the page of a browse node
+func (links Links) BrowseNode(id string) (string, error) {
+	return links.build("/b", url.Values{"node": {id}, "tag": {links.tag}})
+}
+
+// Validate checks that a link points to the store of the marketplace and
+// carries the associate tag of the builder. The links returned by the API,
+// such as Item.DetailPageURL, are also accepted.
+func (links Links) Validate(link string) error {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("amazonpa: invalid link %q", link)
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	region := regionOfHost(host)
+
+	if region == "" {
+		return fmt.Errorf("amazonpa: link host %q is not an Amazon store", host)
+	}
+
+	if region != links.region {
+		return fmt.Errorf("amazonpa: link host %s is not in the %s marketplace", host, links.region)
+	}
+
+	tag := firstValue(parsed.Query(), "tag", "AssociateTag")
+
+	// The API links have their query escaped in the path
+	if tag == "" {
+		if unescaped, err := url.PathUnescape(parsed.EscapedPath()); err == nil {
+			if index := strings.Index(unescaped, "?"); index >= 0 {
+				if query, err := url.ParseQuery(unescaped[index+1:]); err == nil {
+					tag = firstValue(query, "tag", "AssociateTag")
+				}
+			}
+		}
+	}
+
+	if tag == "" {
+		return fmt.Errorf("amazonpa: link has no associate tag")
+	}
+
+	if tag != links.tag {
+		return fmt.Errorf("amazonpa: link carries tag %s instead of %s", tag, links.tag)
+	}
+
+	return nil
+}
+
+func firstValue(values url.Values, keys ...string) string {
+	for _, key := range keys {
+		if value := values.Get(key); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,46 @@
+package amazonpa
+
+import "testing"
+
+func TestLinks(t *testing.T) {
+	links := newTestClient().Links()
+
+	link := func(link string, err error) string {
+		if err != nil {
+			t.Error(err)
+		}
+		return link
+	}
+
+	assertEqualStr(t, link(links.Product("B003TGG2EA")), "https://www.amazon.com/dp/B003TGG2EA/?tag=mytag-20", "Bad product link")
+	assertEqualStr(t, link(links.ForRegion("DE").Search("grohe rubinetto")), "https://www.amazon.de/s?k=grohe+rubinetto&tag=mytag-20", "Bad search link")
+	assertEqualStr(t, link(links.WithTrackingID("spring-20").BrowseNode("3040")), "https://www.amazon.com/b?node=3040&tag=spring-20", "Bad browse node link")
+	assertEqualStr(t, link(links.AddToCart(CartItem{"B003TGG2EA", 2}, CartItem{ASIN: "0134190440"})),
+		"https://www.amazon.com/gp/aws/cart/add.html?ASIN.1=B003TGG2EA&ASIN.2=0134190440&AssociateTag=mytag-20&Quantity.1=2&Quantity.2=1", "Bad cart link")
+
+	if err := links.Validate(link(links.AddToCart(CartItem{ASIN: "B003TGG2EA"}))); err != nil {
+		t.Error(err)
+	}
+	if err := links.Validate("https://www.amazon.com/dp/B003TGG2EA?tag=other-20"); err == nil {
+		t.Error("Links with another tag must be rejected")
+	}
+	if err := links.Validate("https://www.amazon.de/dp/B003TGG2EA?tag=mytag-20"); err == nil {
+		t.Error("Links to another marketplace must be rejected")
+	}
+	if err := links.Validate("https://www.amazon.com/dp/B003TGG2EA"); err == nil {
+		t.Error("Links without tag must be rejected")
+	}
+
+	if _, err := links.ForRegion("XX").Product("B003TGG2EA"); err == nil {
+		t.Error("Links to unknown regions must be rejected")
+	}
+
+	if err := links.ForRegion("").Validate("https://evil.example.com/dp/B003TGG2EA?tag=mytag-20"); err == nil {
+		t.Error("Links to other hosts must be rejected")
+	}
+
+	detailPageURL := "https://www.amazon.it/Grohe-32843000-Cosmopolitan-Miscelatore-Monocomando/dp/B003TGG2EA%3FSubscriptionId%3DAKIAIZL74FSKHXDX66WQ%26tag%3Dgoldbot-21%26linkCode%3Dxm2"
+	if err := links.ForRegion("IT").WithTrackingID("goldbot-21").Validate(detailPageURL); err != nil {
+		t.Error(err)
+	}
+}
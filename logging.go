@@ -0,0 +1,158 @@
+package amazonpa
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RedactedParameters are the request parameters hidden from the logs by
+// default
+var RedactedParameters = []string{"AWSAccessKeyId", "Signature", "AssociateTag"}
+
+// redacted replaces the values of the redacted parameters
+const redacted = "REDACTED"
+
+// LoggingOptions configures the logging middleware
+type LoggingOptions struct {
+	// Redact lists the parameters whose values are hidden. When nil,
+	// RedactedParameters is used; an empty slice disables the redaction.
+	Redact []string
+}
+
+// Logging returns a middleware logging every request to logger, with the
+// operation, region, parameters, duration, HTTP status and API error code.
+// The credentials and the associate tag are redacted.
+func Logging(logger *slog.Logger) Middleware {
+	return LoggingWithOptions(logger, LoggingOptions{})
+}
+
+// LoggingWithOptions returns a logging middleware configured by options
+func LoggingWithOptions(logger *slog.Logger, options LoggingOptions) Middleware {
+	redact := options.Redact
+	if redact == nil {
+		redact = RedactedParameters
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, exchange *Exchange) error {
+			start := time.Now()
+			err := next(ctx, exchange)
+
+			attrs := []slog.Attr{
+				slog.String("operation", exchange.Request.Operation()),
+				slog.String("region", exchange.Request.Region()),
+				redactParameters(exchange.Request.Parameters(), redact),
+				slog.Duration("duration", time.Since(start)),
+			}
+
+			if exchange.SignedURL != "" {
+				attrs = append(attrs, slog.String("url", redactURL(exchange.SignedURL, redact)))
+			}
+
+			if exchange.HTTPResponse != nil {
+				attrs = append(attrs, slog.Int("status", exchange.HTTPResponse.StatusCode))
+			}
+
+			level := slog.LevelInfo
+
+			if err != nil {
+				level = slog.LevelError
+				attrs = append(attrs, slog.String("error", redactText(err.Error(), *exchange.Request, redact)))
+
+				var apiError *APIError
+				if errors.As(err, &apiError) {
+					attrs = append(attrs, slog.String("error_code", apiError.Code))
+				}
+			}
+
+			logger.LogAttrs(ctx, level, "amazonpa request", attrs...)
+
+			return err
+		}
+	}
+}
+
+// redactParameters returns the parameters as a log group, hiding the
+// values of the redacted ones
+func redactParameters(parameters map[string]string, redact []string) slog.Attr {
+	var keys []string
+	for key, value := range parameters {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		value := parameters[key]
+		if contains(redact, key) {
+			value = redacted
+		}
+		attrs = append(attrs, slog.String(key, value))
+	}
+
+	return slog.Group("parameters", attrs...)
+}
+
+// RedactURL hides the values of the given query parameters of a URL, or of
+// RedactedParameters if none is given
+func RedactURL(rawURL string, parameters ...string) string {
+	if len(parameters) == 0 {
+		parameters = RedactedParameters
+	}
+
+	return redactURL(rawURL, parameters)
+}
+
+func redactURL(rawURL string, parameters []string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+
+	query := parsed.Query()
+	for _, parameter := range parameters {
+		if _, ok := query[parameter]; ok {
+			query.Set(parameter, redacted)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// redactText hides the values of the redacted parameters in a text, such
+// as an error message echoing the access key
+func redactText(text string, request Request, redact []string) string {
+	for _, key := range redact {
+		value := request.parameters[key]
+		if key == "Signature" {
+			value = request.signature
+		}
+
+		if value == "" {
+			continue
+		}
+
+		text = strings.ReplaceAll(text, value, redacted)
+		text = strings.ReplaceAll(text, url.QueryEscape(value), redacted)
+	}
+
+	return text
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
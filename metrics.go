@@ -0,0 +1,219 @@
+package amazonpa
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Metrics receives the measurements of the client. Implementations must be
+// safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest is called once for every request sent to the API, with
+	// the HTTP status (0 when no response was received), the API error code
+	// (empty on success) and the latency, excluding the rate limiter wait
+	ObserveRequest(operation string, statusCode int, errorCode string, latency time.Duration)
+
+	// ObserveRateLimitWait is called with the time a request waited for the
+	// rate limiter
+	ObserveRateLimitWait(operation string, wait time.Duration)
+
+	// IncRetries counts a retried request. It is called when a middleware
+	// sends a request again by calling the next handler once more.
+	IncRetries(operation string)
+
+	// IncCacheHits counts a request answered from a cache
+	IncCacheHits(operation string)
+
+	// IncCacheMisses counts a request that was not found in a cache
+	IncCacheMisses(operation string)
+}
+
+// Error codes reported to ObserveRequest for failures that are not API
+// errors. Requests given up by the caller, whose context was canceled or
+// reached its deadline, are reported apart from the network failures.
+const (
+	ErrorCodeDecode    = "DecodeError"
+	ErrorCodeTransport = "TransportError"
+	ErrorCodeCanceled  = "Canceled"
+	ErrorCodeTimeout   = "Timeout"
+)
+
+// SetMetrics sets the collector of the client measurements. Middlewares
+// implementing caches report them through Client.Metrics.
+func (client *Client) SetMetrics(metrics Metrics) {
+	client.metrics = metrics
+}
+
+// Metrics returns the collector of the client measurements, which does
+// nothing if none was set
+func (client Client) Metrics() Metrics {
+	if client.metrics == nil {
+		return MetricsFuncs{}
+	}
+
+	return client.metrics
+}
+
+// errorCode returns the code of an error for the metrics
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.Code
+	}
+
+	var decodeError *DecodeError
+	if errors.As(err, &decodeError) {
+		return ErrorCodeDecode
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ErrorCodeCanceled
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCodeTimeout
+	}
+
+	return ErrorCodeTransport
+}
+
+// MetricsFuncs adapts functions to the Metrics interface, for example to
+// update Prometheus collectors. Nil functions are skipped.
+type MetricsFuncs struct {
+	Request       func(operation string, statusCode int, errorCode string, latency time.Duration)
+	RateLimitWait func(operation string, wait time.Duration)
+	Retry         func(operation string)
+	CacheHit      func(operation string)
+	CacheMiss     func(operation string)
+}
+
+// ObserveRequest calls the Request function
+func (funcs MetricsFuncs) ObserveRequest(operation string, statusCode int, errorCode string, latency time.Duration) {
+	if funcs.Request != nil {
+		funcs.Request(operation, statusCode, errorCode, latency)
+	}
+}
+
+// ObserveRateLimitWait calls the RateLimitWait function
+func (funcs MetricsFuncs) ObserveRateLimitWait(operation string, wait time.Duration) {
+	if funcs.RateLimitWait != nil {
+		funcs.RateLimitWait(operation, wait)
+	}
+}
+
+// IncRetries calls the Retry function
+func (funcs MetricsFuncs) IncRetries(operation string) {
+	if funcs.Retry != nil {
+		funcs.Retry(operation)
+	}
+}
+
+// IncCacheHits calls the CacheHit function
+func (funcs MetricsFuncs) IncCacheHits(operation string) {
+	if funcs.CacheHit != nil {
+		funcs.CacheHit(operation)
+	}
+}
+
+// IncCacheMisses calls the CacheMiss function
+func (funcs MetricsFuncs) IncCacheMisses(operation string) {
+	if funcs.CacheMiss != nil {
+		funcs.CacheMiss(operation)
+	}
+}
+
+// LatencyBuckets are the upper bounds of the latency histogram of
+// ExpvarMetrics
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// ExpvarMetrics publishes the measurements with expvar, in a map with an
+// entry per operation:
+//
+//	{"ItemLookup": {"requests": 12, "status.200": 11, "errors.RequestThrottled": 1,
+//	 "latency.le_0.25": 9, "latency.le_+Inf": 12, "latency_seconds": 2.7, ...}}
+type ExpvarMetrics struct {
+	mutex sync.Mutex
+	vars  *expvar.Map
+}
+
+// NewExpvarMetrics publishes the measurements under the given expvar name.
+// Like expvar.Publish, it panics if the name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{vars: expvar.NewMap(name)}
+}
+
+// operation returns the map of the measurements of an operation
+func (metrics *ExpvarMetrics) operation(operation string) *expvar.Map {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	if vars, ok := metrics.vars.Get(operation).(*expvar.Map); ok {
+		return vars
+	}
+
+	vars := new(expvar.Map).Init()
+	metrics.vars.Set(operation, vars)
+
+	return vars
+}
+
+// ObserveRequest counts the request and updates the latency histogram
+func (metrics *ExpvarMetrics) ObserveRequest(operation string, statusCode int, errorCode string, latency time.Duration) {
+	vars := metrics.operation(operation)
+
+	vars.Add("requests", 1)
+
+	if statusCode != 0 {
+		vars.Add("status."+strconv.Itoa(statusCode), 1)
+	}
+
+	if errorCode != "" {
+		vars.Add("errors."+errorCode, 1)
+	}
+
+	for _, bucket := range LatencyBuckets {
+		if latency <= bucket {
+			vars.Add(fmt.Sprintf("latency.le_%g", bucket.Seconds()), 1)
+		}
+	}
+	vars.Add("latency.le_+Inf", 1)
+	vars.AddFloat("latency_seconds", latency.Seconds())
+}
+
+// ObserveRateLimitWait adds the wait to the total rate limiter wait time
+func (metrics *ExpvarMetrics) ObserveRateLimitWait(operation string, wait time.Duration) {
+	metrics.operation(operation).AddFloat("ratelimit_wait_seconds", wait.Seconds())
+}
+
+// IncRetries counts a retried request
+func (metrics *ExpvarMetrics) IncRetries(operation string) {
+	metrics.operation(operation).Add("retries", 1)
+}
+
+// IncCacheHits counts a cache hit
+func (metrics *ExpvarMetrics) IncCacheHits(operation string) {
+	metrics.operation(operation).Add("cache_hits", 1)
+}
+
+// IncCacheMisses counts a cache miss
+func (metrics *ExpvarMetrics) IncCacheMisses(operation string) {
+	metrics.operation(operation).Add("cache_misses", 1)
+}
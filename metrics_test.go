@@ -0,0 +1,103 @@
+package amazonpa
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	var requests []string
+	var waits int
+
+	client := newFixtureClientWithStatus("testdata/itemlookup_error_response.xml", 503, "text/xml")
+	client.SetRateLimit(1000)
+	client.SetMetrics(MetricsFuncs{
+		Request: func(operation string, statusCode int, errorCode string, latency time.Duration) {
+			requests = append(requests, operation+" "+formatInt(statusCode)+" "+errorCode)
+		},
+		RateLimitWait: func(operation string, wait time.Duration) {
+			waits++
+		},
+	})
+
+	if _, err := client.ItemLookup(ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}}); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	assertEqualInt(t, len(requests), 1, "Bad number of observed requests")
+	assertEqualStr(t, requests[0], "ItemLookup 503 RequestThrottled", "Bad observed request")
+	assertEqualInt(t, waits, 1, "Bad number of observed rate limiter waits")
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	// expvar names cannot be reused when the test is repeated
+	name := fmt.Sprintf("amazonpa_test_%d", time.Now().UnixNano())
+	metrics := NewExpvarMetrics(name)
+
+	client := newFixtureClient("testdata/itemlookup_response.xml")
+	client.SetMetrics(metrics)
+
+	if _, err := client.ItemLookup(ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}}); err != nil {
+		t.Fatal(err)
+	}
+	client.Metrics().IncCacheHits("ItemLookup")
+
+	vars := expvar.Get(name).(*expvar.Map).Get("ItemLookup").(*expvar.Map)
+
+	for _, name := range []string{"requests", "status.200", "latency.le_+Inf", "cache_hits"} {
+		value, ok := vars.Get(name).(*expvar.Int)
+		if !ok {
+			t.Errorf("Missing %s", name)
+			continue
+		}
+		assertEqualInt(t, int(value.Value()), 1, "Bad value of "+name)
+	}
+}
+
+func TestMetricsRetries(t *testing.T) {
+	var retries int
+	var codes []string
+
+	client := newFixtureClientWithStatus("testdata/itemlookup_error_response.xml", 503, "text/xml")
+	client.SetMetrics(MetricsFuncs{
+		Request: func(operation string, statusCode int, errorCode string, latency time.Duration) {
+			codes = append(codes, errorCode)
+		},
+		Retry: func(operation string) {
+			retries++
+		},
+	})
+
+	// Sends every request up to three times
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, exchange *Exchange) (err error) {
+			for i := 0; i < 3; i++ {
+				if err = next(ctx, exchange); err == nil {
+					return nil
+				}
+			}
+			return err
+		}
+	})
+
+	if _, err := client.ItemLookup(ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}}); err == nil {
+		t.Fatal("Expected an error")
+	}
+	assertEqualInt(t, retries, 2, "Bad number of retries")
+	assertEqualInt(t, len(codes), 3, "Bad number of observed requests")
+}
+
+func TestErrorCode(t *testing.T) {
+	for err, code := range map[error]string{
+		context.Canceled:                           ErrorCodeCanceled,
+		context.DeadlineExceeded:                   ErrorCodeTimeout,
+		fmt.Errorf("stream: %w", context.Canceled): ErrorCodeCanceled,
+		errors.New("connection reset"):             ErrorCodeTransport,
+	} {
+		assertEqualStr(t, errorCode(err), code, "Bad error code of "+err.Error())
+	}
+}
@@ -0,0 +1,149 @@
+package amazonpa
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Exchange is a request to the API along with its outcome, as it goes
+// through the middleware chain
+type Exchange struct {
+	// Request is the API request. It is unsigned until it reaches the end of
+	// the chain, so middlewares can still change its parameters.
+	Request *Request
+
+	// Header holds additional headers for the HTTP request
+	Header http.Header
+
+	// SignedURL is the URL that was requested
+	SignedURL string
+
+	// HTTPResponse is the response of the API, whose body has already been
+	// consumed
+	HTTPResponse *http.Response
+
+	// Body is the raw response body. It is nil for streamed requests.
+	Body []byte
+
+	// Result is the value the body is decoded into, or nil when the caller
+	// only needs the raw body
+	Result interface{}
+
+	// stream consumes the body for streamed requests
+	stream func(httpResponse *http.Response) error
+
+	// sent is the time the request was sent, after the rate limiter wait
+	sent time.Time
+
+	// attempts is the number of times the request was sent
+	attempts int
+
+	// items is the number of streamed items
+	items int
+}
+
+// Decode decodes Body into Result, or passes its items to the handler of
+// a streamed request. Middlewares answering a request without calling the
+// next handler, like caches, set Body and call Decode.
+func (exchange *Exchange) Decode() error {
+	if exchange.Result == nil && exchange.stream == nil {
+		return nil
+	}
+
+	if exchange.Body == nil {
+		return errors.New("amazonpa: no response body to decode")
+	}
+
+	if exchange.stream != nil {
+		return exchange.stream(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"text/xml"}},
+			Body:       ioutil.NopCloser(bytes.NewReader(exchange.Body)),
+		})
+	}
+
+	return decodeResponse(exchange.Request.Operation(), exchange.Body, exchange.Result)
+}
+
+// Handler executes an exchange
+type Handler func(ctx context.Context, exchange *Exchange) error
+
+// Middleware wraps a handler, running code around the execution of the
+// requests
+type Middleware func(next Handler) Handler
+
+// Use adds middlewares to the client. The first middleware added is the
+// outermost one.
+func (client *Client) Use(middleware ...Middleware) {
+	client.middleware = append(client.middleware[:len(client.middleware):len(client.middleware)], middleware...)
+}
+
+// do runs the exchange through the middleware chain
+func (client Client) do(ctx context.Context, exchange *Exchange) error {
+	handler := Handler(client.execute)
+
+	for i := len(client.middleware) - 1; i >= 0; i-- {
+		handler = client.middleware[i](handler)
+	}
+
+	ctx, span := client.startSpan(ctx, exchange)
+	err := handler(ctx, exchange)
+	endSpan(span, exchange, err)
+
+	return err
+}
+
+// execute is the last handler of the chain, which performs the request and
+// reads or streams the response
+func (client Client) execute(ctx context.Context, exchange *Exchange) (err error) {
+
+	exchange.attempts++
+	if exchange.attempts > 1 {
+		client.Metrics().IncRetries(exchange.Request.Operation())
+	}
+	httpResponse, err := client.sendRequest(ctx, exchange)
+
+	if err != nil {
+		if !exchange.sent.IsZero() {
+			client.Metrics().ObserveRequest(exchange.Request.Operation(), 0, errorCode(err), time.Since(exchange.sent))
+		}
+		return err
+	}
+
+	defer func() {
+		client.Metrics().ObserveRequest(exchange.Request.Operation(), httpResponse.StatusCode, errorCode(err), time.Since(exchange.sent))
+	}()
+
+	defer httpResponse.Body.Close()
+
+	exchange.HTTPResponse = httpResponse
+
+	if exchange.stream != nil {
+		err = exchange.stream(httpResponse)
+		client.reportResult(exchange.Request, err)
+
+		return err
+	}
+
+	contents, err := readBody(httpResponse)
+
+	if err != nil {
+		return err
+	}
+
+	exchange.Body = contents
+
+	// Check for non-XML content and error responses
+	err = checkResponse(exchange.Request.Operation(), httpResponse.StatusCode, httpResponse.Header.Get("Content-Type"), contents)
+	client.reportResult(exchange.Request, err)
+
+	if err != nil {
+		return err
+	}
+
+	return exchange.Decode()
+}
@@ -0,0 +1,111 @@
+package amazonpa
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	var calls []string
+	var header string
+
+	client := newFuncClient(func(request *http.Request) (*http.Response, error) {
+		header = request.Header.Get("X-Trace")
+		return xmlResponse(http.StatusOK, itemLookupXML("B003TGG2EA")), nil
+	})
+
+	client.Use(
+		func(next Handler) Handler {
+			return func(ctx context.Context, exchange *Exchange) error {
+				calls = append(calls, "outer:"+exchange.Request.Operation())
+				exchange.Header = http.Header{"X-Trace": {"abc"}}
+				return next(ctx, exchange)
+			}
+		},
+		func(next Handler) Handler {
+			return func(ctx context.Context, exchange *Exchange) error {
+				exchange.Request.SetParameter("MerchantId", "Amazon")
+				err := next(ctx, exchange)
+
+				item := exchange.Result.(*ItemLookupResponse).Items.Item
+				calls = append(calls, "inner:"+item.ASIN)
+
+				if !strings.Contains(exchange.SignedURL, "MerchantId=Amazon") {
+					t.Error("Request changed by the middleware must be signed")
+				}
+				assertEqualInt(t, exchange.HTTPResponse.StatusCode, http.StatusOK, "Bad status code")
+
+				return err
+			}
+		},
+	)
+
+	if _, err := client.ItemLookup(ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualStr(t, strings.Join(calls, " "), "outer:ItemLookup inner:B003TGG2EA", "Bad middleware calls")
+	assertEqualStr(t, header, "abc", "Header not injected")
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	cached, err := ioutil.ReadFile("testdata/itemlookup_response.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newFuncClient(func(request *http.Request) (*http.Response, error) {
+		t.Error("Cached request must not be sent")
+		return nil, http.ErrHandlerTimeout
+	})
+
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, exchange *Exchange) error {
+			exchange.Body = cached
+			return exchange.Decode()
+		}
+	})
+
+	response, err := client.ItemLookup(ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqualStr(t, response.Items.Item.ASIN, "B003TGG2EA", "Bad cached ASIN")
+}
+
+func TestMiddlewareShortCircuitStream(t *testing.T) {
+	client := newFuncClient(func(request *http.Request) (*http.Response, error) {
+		t.Error("Cached request must not be sent")
+		return nil, http.ErrHandlerTimeout
+	})
+
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, exchange *Exchange) error {
+			exchange.Body = []byte(itemLookupXML(strings.Split(exchange.Request.Parameters()["ItemId"], ",")...))
+			return exchange.Decode()
+		}
+	})
+
+	items, err := client.LookupItemsContext(context.Background(), ItemLookupQuery{ItemIDs: []string{"B003TGG2EA", "B00000JBLH"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualInt(t, len(items), 2, "Bad number of cached items")
+	assertEqualStr(t, items[1].ASIN, "B00000JBLH", "Bad cached ASIN")
+
+	// A middleware that does not set the body gets an error
+	client = newFixtureClient("testdata/itemlookup_response.xml")
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, exchange *Exchange) error {
+			return exchange.Decode()
+		}
+	})
+
+	if _, err := client.LookupItemsContext(context.Background(), ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}}); err == nil {
+		t.Error("Streamed request without body must fail")
+	}
+}
@@ -0,0 +1,116 @@
+package amazonpa
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CredentialsReporter is implemented by the credentials providers that
+// keep track of the outcome of the requests signed with their credentials
+type CredentialsReporter interface {
+	ReportResult(credentials Credentials, err error)
+}
+
+// ErrNoCredentials is returned by a CredentialPool when all its
+// credentials are benched
+var ErrNoCredentials = errors.New("amazonpa: all the credentials are temporarily benched")
+
+// CredentialPool spreads the requests over several credential sets, each
+// with its own quota and, optionally, associate tag. The sets are used in
+// turn; a set is benched for ThrottleBench after a throttled request and
+// for FailureBench after an InvalidClientTokenId or AccountLimitExceeded
+// error. To aggregate the throughput, the rate limit of the client should
+// be the sum of the limits of the sets.
+type CredentialPool struct {
+	ThrottleBench time.Duration
+	FailureBench  time.Duration
+
+	mutex sync.Mutex
+	sets  []pooledCredentials
+	next  int
+}
+
+type pooledCredentials struct {
+	credentials  Credentials
+	benchedUntil time.Time
+}
+
+// NewCredentialPool returns a pool of the given credential sets
+func NewCredentialPool(sets ...Credentials) *CredentialPool {
+	pool := &CredentialPool{
+		ThrottleBench: 10 * time.Second,
+		FailureBench:  time.Hour,
+	}
+
+	for _, credentials := range sets {
+		pool.sets = append(pool.sets, pooledCredentials{credentials: credentials})
+	}
+
+	return pool
+}
+
+// Credentials returns the next credential set that is not benched
+func (pool *CredentialPool) Credentials() (Credentials, error) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	now := time.Now()
+
+	for i := 0; i < len(pool.sets); i++ {
+		set := pool.sets[(pool.next+i)%len(pool.sets)]
+
+		if !now.Before(set.benchedUntil) {
+			pool.next = (pool.next + i + 1) % len(pool.sets)
+			return set.credentials, nil
+		}
+	}
+
+	return Credentials{}, ErrNoCredentials
+}
+
+// ReportResult benches the credential set after throttling or account errors
+func (pool *CredentialPool) ReportResult(credentials Credentials, err error) {
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		return
+	}
+
+	var bench time.Duration
+	switch {
+	case apiError.Code == "InvalidClientTokenId" || apiError.Code == "AccountLimitExceeded":
+		bench = pool.FailureBench
+	case apiError.Code == "RequestThrottled" || apiError.StatusCode == http.StatusServiceUnavailable:
+		bench = pool.ThrottleBench
+	default:
+		return
+	}
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	for i := range pool.sets {
+		if pool.sets[i].credentials.AccessKey == credentials.AccessKey {
+			pool.sets[i].benchedUntil = time.Now().Add(bench)
+		}
+	}
+}
+
+// Benched returns the access keys of the benched credential sets with the
+// time their bench ends
+func (pool *CredentialPool) Benched() map[string]time.Time {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	benched := map[string]time.Time{}
+	now := time.Now()
+
+	for _, set := range pool.sets {
+		if now.Before(set.benchedUntil) {
+			benched[set.credentials.AccessKey] = set.benchedUntil
+		}
+	}
+
+	return benched
+}
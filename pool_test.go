@@ -0,0 +1,50 @@
+package amazonpa
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCredentialPool(t *testing.T) {
+	var used []string
+	client := newFuncClient(func(request *http.Request) (*http.Response, error) {
+		query := request.URL.Query()
+		used = append(used, query.Get("AWSAccessKeyId")+"/"+query.Get("AssociateTag"))
+
+		body, status := itemLookupXML(), http.StatusOK
+		if query.Get("AWSAccessKeyId") == "AKTHROTTLED" {
+			body, status = "<ItemLookupErrorResponse><Error><Code>RequestThrottled</Code><Message>Slow down</Message></Error></ItemLookupErrorResponse>", http.StatusServiceUnavailable
+		}
+
+		return xmlResponse(status, body), nil
+	})
+
+	pool := NewCredentialPool(
+		Credentials{AccessKey: "AKTHROTTLED", AccessSecret: "a", AssociateTag: "first-20"},
+		Credentials{AccessKey: "AKOK", AccessSecret: "b", AssociateTag: "second-20"},
+	)
+	client.SetCredentialsProvider(pool)
+
+	query := ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}}
+
+	if _, err := client.ItemLookup(query); err == nil {
+		t.Error("Throttled request must fail")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.ItemLookup(query); err != nil {
+			t.Error(err)
+		}
+	}
+
+	assertEqualStr(t, strings.Join(used, " "), "AKTHROTTLED/first-20 AKOK/second-20 AKOK/second-20", "Bad credentials rotation")
+
+	if _, ok := pool.Benched()["AKTHROTTLED"]; !ok {
+		t.Error("Throttled credentials must be benched")
+	}
+
+	pool.ReportResult(Credentials{AccessKey: "AKOK"}, &APIError{Code: "InvalidClientTokenId"})
+	if _, err := pool.Credentials(); err != ErrNoCredentials {
+		t.Errorf("Expected ErrNoCredentials, got %v", err)
+	}
+}
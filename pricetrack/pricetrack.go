@@ -0,0 +1,163 @@
+// Package pricetrack records the prices of items over time, for price
+// history charts and price drop alerts.
+package pricetrack
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattbit/amazonpa"
+)
+
+// Snapshot holds the prices of an item in a marketplace at a given time,
+// from the OfferSummary and Offers response groups
+type Snapshot struct {
+	ASIN            string         `json:"asin"`
+	Marketplace     string         `json:"marketplace"`
+	Time            time.Time      `json:"time"`
+	LowestNewPrice  amazonpa.Price `json:"lowestNewPrice,omitzero"`
+	LowestUsedPrice amazonpa.Price `json:"lowestUsedPrice,omitzero"`
+	OfferPrice      amazonpa.Price `json:"offerPrice,omitzero"`
+	TotalNew        int            `json:"totalNew,omitempty"`
+	TotalUsed       int            `json:"totalUsed,omitempty"`
+}
+
+// NewSnapshot returns the snapshot of the prices of an item. OfferPrice is
+// the price of the first offer, which is the featured one.
+func NewSnapshot(marketplace string, item amazonpa.Item, at time.Time) Snapshot {
+	snapshot := Snapshot{
+		ASIN:            item.ASIN,
+		Marketplace:     marketplace,
+		Time:            at,
+		LowestNewPrice:  item.OfferSummary.LowestNewPrice,
+		LowestUsedPrice: item.OfferSummary.LowerUsedPrice,
+		TotalNew:        item.OfferSummary.TotalNew,
+		TotalUsed:       item.OfferSummary.TotalUsed,
+	}
+
+	if len(item.Offers.Offers) > 0 {
+		snapshot.OfferPrice = item.Offers.Offers[0].Price
+	}
+
+	return snapshot
+}
+
+// Price returns the price tracked by the statistics: the lowest new price,
+// or the offer price if there is none. It is zero when the item is not
+// available.
+func (snapshot Snapshot) Price() amazonpa.Price {
+	if snapshot.LowestNewPrice.Amount > 0 {
+		return snapshot.LowestNewPrice
+	}
+
+	return snapshot.OfferPrice
+}
+
+// Store keeps the snapshots. Implementations must be safe for concurrent
+// use.
+type Store interface {
+	// Append adds a snapshot
+	Append(snapshot Snapshot) error
+
+	// History returns the snapshots of an item in a marketplace taken in
+	// [from, to), sorted by time. A zero from or to leaves the range open
+	// on that side.
+	History(asin, marketplace string, from, to time.Time) ([]Snapshot, error)
+}
+
+// Record appends the snapshots of the items to the store
+func Record(store Store, marketplace string, items []amazonpa.Item, at time.Time) error {
+	for _, item := range items {
+		if err := store.Append(NewSnapshot(marketplace, item, at)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stats are the statistics of the price of an item over a window. The
+// snapshots without a price are ignored.
+type Stats struct {
+	Currency string         `json:"currency,omitempty"`
+	Count    int            `json:"count"`
+	Min      amazonpa.Price `json:"min"`
+	MinAt    time.Time      `json:"minAt"`
+	Max      amazonpa.Price `json:"max"`
+	MaxAt    time.Time      `json:"maxAt"`
+	Average  float64        `json:"average"`
+}
+
+// WindowStats returns the statistics of the price of an item in [from, to).
+// The average is in the same unit as Price.Amount. An error is returned if
+// the prices of the window are not all in the same currency.
+func WindowStats(store Store, asin, marketplace string, from, to time.Time) (Stats, error) {
+	history, err := store.History(asin, marketplace, from, to)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	var stats Stats
+	var total float64
+
+	for _, snapshot := range history {
+		price := snapshot.Price()
+		if price.Amount == 0 {
+			continue
+		}
+
+		if stats.Count == 0 {
+			stats.Currency = price.CurrencyCode
+		} else if price.CurrencyCode != stats.Currency {
+			return Stats{}, fmt.Errorf("pricetrack: prices of %s in %s in different currencies (%s and %s)", asin, marketplace, stats.Currency, price.CurrencyCode)
+		}
+
+		if stats.Count == 0 || price.Amount < stats.Min.Amount {
+			stats.Min, stats.MinAt = price, snapshot.Time
+		}
+
+		if stats.Count == 0 || price.Amount > stats.Max.Amount {
+			stats.Max, stats.MaxAt = price, snapshot.Time
+		}
+
+		stats.Count++
+		total += float64(price.Amount)
+	}
+
+	if stats.Count > 0 {
+		stats.Average = total / float64(stats.Count)
+	}
+
+	return stats, nil
+}
+
+// Change is a change of the price of an item between two snapshots
+type Change struct {
+	Before Snapshot `json:"before"`
+	After  Snapshot `json:"after"`
+}
+
+// Delta returns the difference between the prices, negative for a price
+// drop
+func (change Change) Delta() int64 {
+	return int64(change.After.Price().Amount) - int64(change.Before.Price().Amount)
+}
+
+// LatestChange returns the latest change of the price of an item, or nil
+// if the price never changed
+func LatestChange(store Store, asin, marketplace string) (*Change, error) {
+	history, err := store.History(asin, marketplace, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(history) - 1; i > 0; i-- {
+		before, after := history[i-1].Price(), history[i].Price()
+
+		if before.Amount != after.Amount || before.CurrencyCode != after.CurrencyCode {
+			return &Change{Before: history[i-1], After: history[i]}, nil
+		}
+	}
+
+	return nil, nil
+}
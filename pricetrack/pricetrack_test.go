@@ -0,0 +1,165 @@
+package pricetrack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattbit/amazonpa"
+)
+
+var start = time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func snapshot(day int, amount uint) Snapshot {
+	return Snapshot{
+		ASIN:           "B003TGG2EA",
+		Marketplace:    "DE",
+		Time:           start.AddDate(0, 0, day),
+		LowestNewPrice: amazonpa.Price{Amount: amount, CurrencyCode: "EUR"},
+	}
+}
+
+func fill(t *testing.T, store Store) {
+	// Out of order on purpose
+	for _, s := range []Snapshot{snapshot(0, 18500), snapshot(2, 17900), snapshot(1, 18500), snapshot(3, 0), snapshot(4, 17900)} {
+		if err := store.Append(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWindowStats(t *testing.T) {
+	store := NewMemoryStore()
+	fill(t, store)
+
+	stats, err := WindowStats(store, "B003TGG2EA", "DE", start, start.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Count != 3 || stats.Min.Amount != 17900 || stats.Max.Amount != 18500 {
+		t.Errorf("Bad stats %+v", stats)
+	}
+	if !stats.MinAt.Equal(start.AddDate(0, 0, 2)) || !stats.MaxAt.Equal(start) {
+		t.Errorf("Bad stats times %+v", stats)
+	}
+	if stats.Currency != "EUR" {
+		t.Errorf("Bad currency %q", stats.Currency)
+	}
+	if stats.Average != (18500+18500+17900)/3.0 {
+		t.Errorf("Bad average %v", stats.Average)
+	}
+
+	// The unavailable item is ignored
+	stats, _ = WindowStats(store, "B003TGG2EA", "DE", start.AddDate(0, 0, 3), time.Time{})
+	if stats.Count != 1 {
+		t.Errorf("Bad stats %+v", stats)
+	}
+
+	stats, _ = WindowStats(store, "B003TGG2EA", "US", time.Time{}, time.Time{})
+	if stats.Count != 0 {
+		t.Errorf("Bad stats of another marketplace %+v", stats)
+	}
+}
+
+func TestWindowStatsCurrencies(t *testing.T) {
+	store := NewMemoryStore()
+	fill(t, store)
+
+	dollars := snapshot(5, 19900)
+	dollars.LowestNewPrice.CurrencyCode = "USD"
+	store.Append(dollars)
+
+	if _, err := WindowStats(store, "B003TGG2EA", "DE", time.Time{}, time.Time{}); err == nil {
+		t.Error("Expected an error for prices in different currencies")
+	}
+
+	if _, err := WindowStats(store, "B003TGG2EA", "DE", time.Time{}, start.AddDate(0, 0, 5)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLatestChange(t *testing.T) {
+	store := NewMemoryStore()
+	fill(t, store)
+
+	change, err := LatestChange(store, "B003TGG2EA", "DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if change == nil || !change.After.Time.Equal(start.AddDate(0, 0, 4)) || change.Delta() != 17900 {
+		t.Errorf("Bad latest change %+v", change)
+	}
+
+	store = NewMemoryStore()
+	store.Append(snapshot(0, 100))
+	store.Append(snapshot(1, 100))
+
+	if change, _ := LatestChange(store, "B003TGG2EA", "DE"); change != nil {
+		t.Errorf("Unexpected change %+v", change)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.jsonl")
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(t, store)
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash in the middle of a write
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"asin":"B003TGG2EA","mark`)
+	file.Close()
+
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.Append(snapshot(5, 17500)); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := store.History("B003TGG2EA", "DE", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 6 || history[5].LowestNewPrice.Amount != 17500 {
+		t.Fatalf("Bad history %+v", history)
+	}
+
+	for i := 1; i < len(history); i++ {
+		if history[i].Time.Before(history[i-1].Time) {
+			t.Errorf("History is not sorted: %+v", history)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	item := amazonpa.Item{ASIN: "B003TGG2EA"}
+	item.OfferSummary.LowestNewPrice = amazonpa.Price{Amount: 18500, CurrencyCode: "EUR"}
+	item.Offers.Offers = []amazonpa.Offer{{Price: amazonpa.Price{Amount: 18900, CurrencyCode: "EUR"}}}
+
+	store := NewMemoryStore()
+	if err := Record(store, "DE", []amazonpa.Item{item}, start); err != nil {
+		t.Fatal(err)
+	}
+
+	history, _ := store.History("B003TGG2EA", "DE", time.Time{}, time.Time{})
+	if len(history) != 1 || history[0].Price().Amount != 18500 || history[0].OfferPrice.Amount != 18900 {
+		t.Errorf("Bad recorded history %+v", history)
+	}
+}
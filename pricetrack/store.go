@@ -0,0 +1,206 @@
+package pricetrack
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// key identifies the history of an item in a marketplace
+type key struct {
+	asin        string
+	marketplace string
+}
+
+// MemoryStore keeps the snapshots in memory
+type MemoryStore struct {
+	mutex     sync.RWMutex
+	histories map[key][]Snapshot
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{histories: map[key][]Snapshot{}}
+}
+
+// Append adds a snapshot, keeping the history sorted by time
+func (store *MemoryStore) Append(snapshot Snapshot) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	k := key{snapshot.ASIN, snapshot.Marketplace}
+	history := store.histories[k]
+
+	// Snapshots usually come in order, so search from the end
+	i := len(history)
+	for i > 0 && history[i-1].Time.After(snapshot.Time) {
+		i--
+	}
+
+	history = append(history, Snapshot{})
+	copy(history[i+1:], history[i:])
+	history[i] = snapshot
+
+	store.histories[k] = history
+
+	return nil
+}
+
+// History returns the snapshots of an item in a marketplace taken in
+// [from, to)
+func (store *MemoryStore) History(asin, marketplace string, from, to time.Time) ([]Snapshot, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	history := store.histories[key{asin, marketplace}]
+
+	start := 0
+	if !from.IsZero() {
+		start = sort.Search(len(history), func(i int) bool {
+			return !history[i].Time.Before(from)
+		})
+	}
+
+	end := len(history)
+	if !to.IsZero() {
+		end = sort.Search(len(history), func(i int) bool {
+			return !history[i].Time.Before(to)
+		})
+	}
+
+	if start >= end {
+		return nil, nil
+	}
+
+	return append([]Snapshot(nil), history[start:end]...), nil
+}
+
+// FileStore appends the snapshots to a file, one JSON object per line, and
+// keeps them in memory for the queries. The file is never rewritten, but
+// the whole history is loaded in memory, so it suits histories that fit in
+// memory.
+type FileStore struct {
+	memory *MemoryStore
+
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// OpenFileStore opens the file of a FileStore, creating it if needed, and
+// loads its snapshots. A line left incomplete by a crash is discarded.
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &FileStore{memory: NewMemoryStore(), file: file}
+
+	if err := store.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// load reads the snapshots of the file and truncates it after the last
+// complete line
+func (store *FileStore) load() error {
+	reader := bufio.NewReader(store.file)
+	var size int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		size += int64(len(line))
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var snapshot Snapshot
+		if err := json.Unmarshal(line, &snapshot); err != nil {
+			return errors.New("pricetrack: invalid snapshot in " + store.file.Name() + ": " + err.Error())
+		}
+
+		store.memory.Append(snapshot)
+	}
+
+	if err := store.file.Truncate(size); err != nil {
+		return err
+	}
+
+	_, err := store.file.Seek(size, io.SeekStart)
+
+	return err
+}
+
+// Append writes the snapshot to the file
+func (store *FileStore) Append(snapshot Snapshot) error {
+	line, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.file == nil {
+		return errors.New("pricetrack: store is closed")
+	}
+
+	if _, err := store.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return store.memory.Append(snapshot)
+}
+
+// History returns the snapshots of an item in a marketplace taken in
+// [from, to)
+func (store *FileStore) History(asin, marketplace string, from, to time.Time) ([]Snapshot, error) {
+	return store.memory.History(asin, marketplace, from, to)
+}
+
+// Sync commits the file to stable storage
+func (store *FileStore) Sync() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.file == nil {
+		return errors.New("pricetrack: store is closed")
+	}
+
+	return store.file.Sync()
+}
+
+// Close closes the file
+func (store *FileStore) Close() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.file == nil {
+		return nil
+	}
+
+	err := store.file.Close()
+	store.file = nil
+
+	return err
+}
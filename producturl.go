@@ -0,0 +1,105 @@
+package amazonpa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrNotProductURL is returned for URLs that do not point to a product page
+var ErrNotProductURL = errors.New("amazonpa: not an Amazon product URL")
+
+// ErrShortURL is returned by ParseProductURL for short links (such as
+// amzn.to), which must be resolved with Client.ResolveProductURL
+var ErrShortURL = errors.New("amazonpa: short product URL must be resolved")
+
+// shortURLHosts are the hosts of the Amazon short links
+var shortURLHosts = map[string]bool{
+	"a.co":      true,
+	"amzn.asia": true,
+	"amzn.com":  true,
+	"amzn.eu":   true,
+	"amzn.to":   true,
+}
+
+// productPath matches the ASIN in the paths of the product pages
+var productPath = regexp.MustCompile(`(?i)/(?:dp|dp/product|gp/product|gp/aw/d|gp/offer-listing|exec/obidos/ASIN|exec/obidos/tg/detail/-|o/ASIN)/([A-Z0-9]{10})(?:[/?]|$)`)
+
+// ParseProductURL returns the ASIN and the region (as in Endpoints) of an
+// Amazon product URL in the /dp/, /gp/product/ or /exec/obidos/ASIN/ forms
+func ParseProductURL(rawURL string) (asin string, region string, err error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", ErrNotProductURL
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+
+	if shortURLHosts[strings.TrimPrefix(host, "www.")] {
+		return "", "", ErrShortURL
+	}
+
+	region = regionOfHost(host)
+	if region == "" {
+		return "", "", ErrNotProductURL
+	}
+
+	match := productPath.FindStringSubmatch(parsed.EscapedPath())
+	if match == nil {
+		return "", "", ErrNotProductURL
+	}
+
+	return strings.ToUpper(match[1]), region, nil
+}
+
+// regionOfHost returns the region of an Amazon store host, such as
+// www.amazon.de or smile.amazon.com
+func regionOfHost(host string) string {
+	for region, endpoint := range Endpoints {
+		domain := strings.TrimPrefix(endpoint, "webservices.")
+
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return region
+		}
+	}
+
+	return ""
+}
+
+// ResolveProductURL returns the ASIN and the region of an Amazon product
+// URL like ParseProductURL, following the redirect of short links
+func (client Client) ResolveProductURL(ctx context.Context, rawURL string) (asin string, region string, err error) {
+	asin, region, err = ParseProductURL(rawURL)
+	if err != ErrShortURL {
+		return asin, region, err
+	}
+
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodHead, rawURL, nil)
+	if err != nil {
+		return "", "", ErrNotProductURL
+	}
+
+	httpResponse, err := client.httpClient.Do(httpRequest)
+	if err != nil {
+		return "", "", errors.New("amazonpa: cannot resolve the short product URL")
+	}
+	httpResponse.Body.Close()
+
+	resolved := httpResponse.Request.URL.String()
+	if shortURLHosts[strings.TrimPrefix(strings.ToLower(httpResponse.Request.URL.Hostname()), "www.")] {
+		return "", "", ErrNotProductURL
+	}
+
+	return ParseProductURL(resolved)
+}
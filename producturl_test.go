@@ -0,0 +1,57 @@
+package amazonpa
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestParseProductURL(t *testing.T) {
+	cases := map[string][2]string{
+		"https://www.amazon.de/Grohe-32843000-Cosmopolitan/dp/B003TGG2EA/ref=sr_1_1?keywords=grohe": {"B003TGG2EA", "DE"},
+		"http://www.amazon.co.jp/gp/product/4873117526":                                             {"4873117526", "JP"},
+		"https://www.amazon.com/exec/obidos/ASIN/0134190440/mytag-20":                               {"0134190440", "US"},
+		"https://smile.amazon.com/dp/b003tgg2ea":                                                    {"B003TGG2EA", "US"},
+		"amazon.co.uk/gp/aw/d/B003TGG2EA":                                                           {"B003TGG2EA", "UK"},
+		"https://www.amazon.com.br/dp/B003TGG2EA?tag=mytag-20":                                      {"B003TGG2EA", "BR"},
+	}
+
+	for rawURL, expected := range cases {
+		asin, region, err := ParseProductURL(rawURL)
+		if err != nil || asin != expected[0] || region != expected[1] {
+			t.Errorf("URL %s parsed as %s/%s (%v)", rawURL, asin, region, err)
+		}
+	}
+
+	invalid := map[string]error{
+		"https://www.amazon.de/s?k=grohe":        ErrNotProductURL,
+		"https://www.example.com/dp/B003TGG2EA":  ErrNotProductURL,
+		"https://www.amazon.com/dp/B003TGG2EAXX": ErrNotProductURL,
+		"https://amzn.to/2xYz9Ab":                ErrShortURL,
+		"https://amzn.eu/d/4Vh1Xyz":              ErrShortURL,
+	}
+
+	for rawURL, expected := range invalid {
+		if _, _, err := ParseProductURL(rawURL); err != expected {
+			t.Errorf("URL %s must fail with %v, got %v", rawURL, expected, err)
+		}
+	}
+}
+
+func TestResolveProductURL(t *testing.T) {
+	client := newFuncClient(func(request *http.Request) (*http.Response, error) {
+		response := &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: request}
+
+		if request.URL.Host == "amzn.to" {
+			response.StatusCode = http.StatusMovedPermanently
+			response.Header = http.Header{"Location": {"https://www.amazon.it/dp/B003TGG2EA?tag=mytag-21"}}
+		}
+
+		return response, nil
+	})
+
+	asin, region, err := client.ResolveProductURL(context.Background(), "https://amzn.to/2xYz9Ab")
+	if err != nil || asin != "B003TGG2EA" || region != "IT" {
+		t.Errorf("Short URL resolved as %s/%s (%v)", asin, region, err)
+	}
+}
@@ -0,0 +1,182 @@
+package amazonpa
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExhausted is matched by the QuotaError returned when the daily
+// quota set with SetDailyQuota has been used
+var ErrQuotaExhausted = errors.New("amazonpa: daily quota exhausted")
+
+// QuotaError is returned instead of sending a request over the daily quota
+// of the access key
+type QuotaError struct {
+	AccessKey string
+	Quota     int
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("amazonpa: daily quota of %d requests exhausted for %s", e.Quota, maskAccessKey(e.AccessKey))
+}
+
+// maskAccessKey hides all but the last characters of an access key, which
+// is enough to tell the keys apart in messages
+func maskAccessKey(accessKey string) string {
+	const visible = 4
+
+	if len(accessKey) <= visible {
+		return strings.Repeat("*", len(accessKey))
+	}
+
+	return strings.Repeat("*", len(accessKey)-visible) + accessKey[len(accessKey)-visible:]
+}
+
+// Is makes errors.Is(err, ErrQuotaExhausted) report true
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExhausted
+}
+
+// Usage is the number of requests sent with an access key in the last
+// second, hour and day. The hour and day counts have a one minute
+// resolution.
+type Usage struct {
+	AccessKey  string `json:"accessKey"`
+	LastSecond int    `json:"lastSecond"`
+	LastHour   int    `json:"lastHour"`
+	LastDay    int    `json:"lastDay"`
+	DailyQuota int    `json:"dailyQuota,omitempty"`
+}
+
+// SetDailyQuota caps the requests sent with each access key in the last 24
+// hours. Over the quota the requests fail with a QuotaError instead of
+// being throttled by the API. A non-positive value removes the cap.
+func (client *Client) SetDailyQuota(requests int) {
+	client.quota.setDailyQuota(requests)
+}
+
+// Usage returns the usage of every access key the client sent requests
+// with, sorted by access key
+func (client Client) Usage() []Usage {
+	return client.quota.usage(time.Now())
+}
+
+// counter is the number of requests in a time slot
+type counter struct {
+	slot  int64
+	count int
+}
+
+// keyUsage keeps the requests of an access key per second, for the last
+// minute, and per minute, for the last day
+type keyUsage struct {
+	seconds [60]counter
+	minutes [24 * 60]counter
+}
+
+func (usage *keyUsage) add(now time.Time) {
+	second := now.Unix()
+	minute := second / 60
+
+	increment(&usage.seconds[second%int64(len(usage.seconds))], second)
+	increment(&usage.minutes[minute%int64(len(usage.minutes))], minute)
+}
+
+func increment(c *counter, slot int64) {
+	if c.slot != slot {
+		*c = counter{slot: slot}
+	}
+	c.count++
+}
+
+// since sums the counters of the slots after from
+func since(counters []counter, from int64) int {
+	total := 0
+	for _, c := range counters {
+		if c.slot > from {
+			total += c.count
+		}
+	}
+
+	return total
+}
+
+func (usage *keyUsage) lastSecond(now time.Time) int {
+	return since(usage.seconds[:], now.Unix()-1)
+}
+
+func (usage *keyUsage) lastHour(now time.Time) int {
+	return since(usage.minutes[:], now.Unix()/60-60)
+}
+
+func (usage *keyUsage) lastDay(now time.Time) int {
+	return since(usage.minutes[:], now.Unix()/60-24*60)
+}
+
+// quotaTracker counts the requests of every access key
+type quotaTracker struct {
+	mutex      sync.Mutex
+	dailyQuota int
+	keys       map[string]*keyUsage
+}
+
+func newQuotaTracker() *quotaTracker {
+	return &quotaTracker{keys: map[string]*keyUsage{}}
+}
+
+func (tracker *quotaTracker) setDailyQuota(requests int) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if requests < 0 {
+		requests = 0
+	}
+	tracker.dailyQuota = requests
+}
+
+// acquire counts a request with the access key, unless it is over the
+// daily quota
+func (tracker *quotaTracker) acquire(accessKey string, now time.Time) error {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	usage, ok := tracker.keys[accessKey]
+	if !ok {
+		usage = &keyUsage{}
+		tracker.keys[accessKey] = usage
+	}
+
+	if tracker.dailyQuota > 0 && usage.lastDay(now) >= tracker.dailyQuota {
+		return &QuotaError{AccessKey: accessKey, Quota: tracker.dailyQuota}
+	}
+
+	usage.add(now)
+
+	return nil
+}
+
+func (tracker *quotaTracker) usage(now time.Time) []Usage {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	usages := make([]Usage, 0, len(tracker.keys))
+	for accessKey, usage := range tracker.keys {
+		usages = append(usages, Usage{
+			AccessKey:  accessKey,
+			LastSecond: usage.lastSecond(now),
+			LastHour:   usage.lastHour(now),
+			LastDay:    usage.lastDay(now),
+			DailyQuota: tracker.dailyQuota,
+		})
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].AccessKey < usages[j].AccessKey
+	})
+
+	return usages
+}
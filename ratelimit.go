@@ -0,0 +1,61 @@
+package amazonpa
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter delays the requests of the client
+type limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// rateLimiter spaces out the requests to a maximum rate
+type rateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// Wait blocks until a request can be performed or the context is done
+func (limiter *rateLimiter) Wait(ctx context.Context) error {
+	limiter.mutex.Lock()
+	now := time.Now()
+	slot := limiter.next
+	if slot.Before(now) {
+		slot = now
+	}
+	limiter.next = slot.Add(limiter.interval)
+	limiter.mutex.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		limiter.release(slot)
+		return ctx.Err()
+	}
+}
+
+// release gives back a slot that was reserved but not used
+func (limiter *rateLimiter) release(slot time.Time) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	if limiter.next.Equal(slot.Add(limiter.interval)) {
+		limiter.next = slot
+	}
+}
@@ -0,0 +1,336 @@
+// Package refresh looks up large lists of items as a resumable job, which
+// saves its progress to a checkpoint file and carries on after a restart.
+package refresh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mattbit/amazonpa"
+)
+
+// Handler receives the items of every successful batch. If it returns an
+// error the job stops, and the batch is looked up again when it resumes.
+type Handler func(items []amazonpa.Item) error
+
+// Default retry settings of a Job
+const (
+	DefaultMaxAttempts   = 5
+	DefaultRetryDelay    = time.Minute
+	DefaultMaxRetryDelay = time.Hour
+)
+
+// ErrRunning is returned by Run when the job is already running
+var ErrRunning = errors.New("refresh: job is already running")
+
+// Progress is a snapshot of the progress of a job. Done and Failed count
+// the item IDs looked up and given up on, Retrying the ones of the failed
+// batches waiting to be retried and Remaining the ones not looked up yet,
+// retries included. ETA is estimated from the rate of the current run.
+type Progress struct {
+	Done      int           `json:"done"`
+	Failed    int           `json:"failed"`
+	Retrying  int           `json:"retrying"`
+	Remaining int           `json:"remaining"`
+	ETA       time.Duration `json:"eta"`
+}
+
+// batch is a batch of item IDs, with its failed attempts
+type batch struct {
+	ItemIDs  []string  `json:"itemIds"`
+	Attempts int       `json:"attempts"`
+	RetryAt  time.Time `json:"retryAt"`
+	Error    string    `json:"error"`
+}
+
+// checkpoint is the state of a job saved to disk
+type checkpoint struct {
+	Pending []string `json:"pending"`
+	Retries []batch  `json:"retries,omitempty"`
+	Failed  []batch  `json:"failed,omitempty"`
+	Done    int      `json:"done"`
+}
+
+// Job looks up item IDs in batches of amazonpa.MaxItemIDs. The state of
+// the job is saved to the checkpoint file after every batch. A failed batch
+// is retried after RetryDelay, doubled at every attempt up to
+// MaxRetryDelay, and given up on after MaxAttempts.
+type Job struct {
+	MaxAttempts   int
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+
+	// OnProgress, if set, is called after every batch
+	OnProgress func(Progress)
+
+	client  *amazonpa.Client
+	query   amazonpa.ItemLookupQuery
+	path    string
+	handler Handler
+
+	mutex   sync.Mutex
+	state   checkpoint
+	running bool
+
+	// Rate of the current run, for the ETA
+	started time.Time
+	runDone int
+}
+
+// NewJob returns a job looking up the items with the parameters of query,
+// whose ItemIDs are ignored. If the checkpoint file exists, the job resumes
+// from it.
+func NewJob(client *amazonpa.Client, path string, query amazonpa.ItemLookupQuery, handler Handler) (*Job, error) {
+	job := &Job{
+		MaxAttempts:   DefaultMaxAttempts,
+		RetryDelay:    DefaultRetryDelay,
+		MaxRetryDelay: DefaultMaxRetryDelay,
+		client:        client,
+		query:         query,
+		path:          path,
+		handler:       handler,
+	}
+
+	data, err := os.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return job, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &job.state); err != nil {
+		return nil, errors.New("refresh: invalid checkpoint file " + path + ": " + err.Error())
+	}
+
+	return job, nil
+}
+
+// Add queues item IDs to be looked up and saves the checkpoint
+func (job *Job) Add(itemIDs ...string) error {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.state.Pending = append(job.state.Pending, itemIDs...)
+
+	return job.save()
+}
+
+// Progress returns the progress of the job
+func (job *Job) Progress() Progress {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	return job.progress(time.Now())
+}
+
+func (job *Job) progress(now time.Time) Progress {
+	progress := Progress{Done: job.state.Done}
+
+	for _, failed := range job.state.Failed {
+		progress.Failed += len(failed.ItemIDs)
+	}
+
+	for _, retry := range job.state.Retries {
+		progress.Retrying += len(retry.ItemIDs)
+	}
+
+	progress.Remaining = len(job.state.Pending) + progress.Retrying
+
+	if job.runDone > 0 {
+		perItem := now.Sub(job.started) / time.Duration(job.runDone)
+		progress.ETA = perItem * time.Duration(progress.Remaining)
+	}
+
+	return progress
+}
+
+// Run looks up the queued items until none are left or the context is
+// done. A job stopped by the context or by the handler can be run again,
+// in the same process or after a restart. A job runs one batch at a time:
+// calling Run while it is running returns ErrRunning.
+func (job *Job) Run(ctx context.Context) error {
+	job.mutex.Lock()
+	if job.running {
+		job.mutex.Unlock()
+		return ErrRunning
+	}
+	job.running = true
+	job.started = time.Now()
+	job.runDone = 0
+	job.mutex.Unlock()
+
+	defer func() {
+		job.mutex.Lock()
+		job.running = false
+		job.mutex.Unlock()
+	}()
+
+	for {
+		next, index, wait, ok := job.next(time.Now())
+
+		if !ok {
+			return nil
+		}
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+
+			continue
+		}
+
+		query := job.query
+		query.ItemIDs = next.ItemIDs
+
+		items, err := job.client.LookupItemsContext(ctx, query)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err == nil {
+			if err := job.handler(items); err != nil {
+				return err
+			}
+		}
+
+		progress, err := job.complete(next, index, err)
+		if err != nil {
+			return err
+		}
+
+		if job.OnProgress != nil {
+			job.OnProgress(progress)
+		}
+	}
+}
+
+// next returns the next batch to look up, with its index in the retries
+// or -1 for pending items, or how long to wait for the next retry. It
+// returns false when there is nothing left to do.
+func (job *Job) next(now time.Time) (batch, int, time.Duration, bool) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	var wait time.Duration
+
+	for i, retry := range job.state.Retries {
+		if !retry.RetryAt.After(now) {
+			return retry, i, 0, true
+		}
+
+		if delay := retry.RetryAt.Sub(now); wait == 0 || delay < wait {
+			wait = delay
+		}
+	}
+
+	if len(job.state.Pending) > 0 {
+		end := amazonpa.MaxItemIDs
+		if end > len(job.state.Pending) {
+			end = len(job.state.Pending)
+		}
+
+		itemIDs := append([]string(nil), job.state.Pending[:end]...)
+
+		return batch{ItemIDs: itemIDs}, -1, 0, true
+	}
+
+	return batch{}, -1, wait, wait > 0
+}
+
+// complete records the outcome of a batch returned by next and saves the
+// checkpoint
+func (job *Job) complete(done batch, index int, err error) (Progress, error) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	// Remove the batch from the pending items or the retries
+	if index < 0 {
+		job.state.Pending = job.state.Pending[len(done.ItemIDs):]
+	} else {
+		job.state.Retries = append(job.state.Retries[:index], job.state.Retries[index+1:]...)
+	}
+
+	if err == nil {
+		job.state.Done += len(done.ItemIDs)
+		job.runDone += len(done.ItemIDs)
+	} else {
+		done.Attempts++
+		done.Error = err.Error()
+
+		if done.Attempts >= job.MaxAttempts {
+			done.RetryAt = time.Time{}
+			job.state.Failed = append(job.state.Failed, done)
+		} else {
+			done.RetryAt = time.Now().Add(job.retryDelay(done.Attempts))
+			job.state.Retries = append(job.state.Retries, done)
+		}
+	}
+
+	if err := job.save(); err != nil {
+		return Progress{}, err
+	}
+
+	return job.progress(time.Now()), nil
+}
+
+// retryDelay returns the delay before retrying a batch after its failed
+// attempts: RetryDelay doubled at every attempt, up to MaxRetryDelay
+func (job *Job) retryDelay(attempts int) time.Duration {
+	maxDelay := job.MaxRetryDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxRetryDelay
+	}
+
+	// Double step by step, as a shift by the attempts would overflow
+	delay := job.RetryDelay
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
+
+// save writes the checkpoint file, replacing it atomically
+func (job *Job) save() error {
+	data, err := json.Marshal(job.state)
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(job.path), filepath.Base(job.path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return err
+	}
+
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+
+	return os.Rename(temp.Name(), job.path)
+}
@@ -0,0 +1,209 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattbit/amazonpa"
+)
+
+// failingTransport answers with the item lookup fixture, or an error for
+// the requests of the item IDs containing BAD
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if strings.Contains(request.URL.Query().Get("ItemId"), "BAD") {
+		return nil, errors.New("connection reset")
+	}
+
+	body, err := ioutil.ReadFile("../testdata/itemlookup_response.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/xml"}},
+		Body:       ioutil.NopCloser(strings.NewReader(string(body))),
+	}, nil
+}
+
+func newTestClient() *amazonpa.Client {
+	client := amazonpa.NewClient(amazonpa.Config{AccessKey: "AK", AccessSecret: "secret", AssociateTag: "tag-20", Region: "US"})
+	client.SetHTTPClient(&http.Client{Transport: failingTransport{}})
+
+	return client
+}
+
+func itemIDs(prefix string, count int) []string {
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s%07d", prefix, i)
+	}
+
+	return ids
+}
+
+func TestJobRetries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	batches := 0
+	job, err := NewJob(newTestClient(), path, amazonpa.ItemLookupQuery{}, func(items []amazonpa.Item) error {
+		batches++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job.MaxAttempts = 2
+	job.RetryDelay = time.Millisecond
+
+	if err := job.Add(itemIDs("OK", 20)...); err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Add(itemIDs("BAD", 5)...); err != nil {
+		t.Fatal(err)
+	}
+
+	var updates int
+	job.OnProgress = func(Progress) { updates++ }
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	progress := job.Progress()
+	if progress.Done != 20 || progress.Failed != 5 || progress.Retrying != 0 || progress.Remaining != 0 {
+		t.Errorf("Bad progress %+v", progress)
+	}
+	if batches != 2 {
+		t.Errorf("Expected 2 successful batches, got %d", batches)
+	}
+	if updates != 4 {
+		t.Errorf("Expected 4 progress updates, got %d", updates)
+	}
+}
+
+func TestJobResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	stop := errors.New("deploy")
+
+	job, err := NewJob(newTestClient(), path, amazonpa.ItemLookupQuery{}, func(items []amazonpa.Item) error {
+		return stop
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := job.Add(itemIDs("OK", 25)...); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := job.Run(context.Background()); err != stop {
+		t.Fatalf("Expected the handler error, got %v", err)
+	}
+
+	// A new process resumes from the checkpoint
+	batches := 0
+	resumed, err := NewJob(newTestClient(), path, amazonpa.ItemLookupQuery{}, func(items []amazonpa.Item) error {
+		batches++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := resumed.Progress().Remaining; remaining != 25 {
+		t.Errorf("Expected 25 remaining items, got %d", remaining)
+	}
+
+	if err := resumed.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if batches != 3 || resumed.Progress().Done != 25 {
+		t.Errorf("Bad resumed job: %d batches, progress %+v", batches, resumed.Progress())
+	}
+}
+
+func TestJobCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	job, err := NewJob(newTestClient(), path, amazonpa.ItemLookupQuery{}, func(items []amazonpa.Item) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job.RetryDelay = time.Hour
+
+	if err := job.Add(itemIDs("BAD", 3)...); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The failed batch waits for its retry until the context is done
+	if err := job.Run(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if progress := job.Progress(); progress.Retrying != 3 {
+		t.Errorf("Bad progress %+v", progress)
+	}
+}
+
+func TestJobRetryDelay(t *testing.T) {
+	job := &Job{RetryDelay: time.Minute, MaxRetryDelay: time.Hour}
+
+	for attempts, expected := range map[int]time.Duration{1: time.Minute, 3: 4 * time.Minute, 7: time.Hour, 100: time.Hour} {
+		if delay := job.retryDelay(attempts); delay != expected {
+			t.Errorf("Bad delay after %d attempts: %s instead of %s", attempts, delay, expected)
+		}
+	}
+}
+
+func TestJobRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	job, err := NewJob(newTestClient(), path, amazonpa.ItemLookupQuery{}, func(items []amazonpa.Item) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job.RetryDelay = time.Hour
+
+	if err := job.Add(itemIDs("BAD", 3)...); err != nil {
+		t.Fatal(err)
+	}
+
+	progressed := make(chan struct{}, 1)
+	job.OnProgress = func(Progress) { progressed <- struct{}{} }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- job.Run(ctx) }()
+
+	// The first run waits for the retry of the failed batch
+	<-progressed
+	if err := job.Run(ctx); err != ErrRunning {
+		t.Errorf("Expected ErrRunning, got %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -16,6 +16,7 @@ type Request struct {
 	endpointURI string
 	signature   string
 	parameters  map[string]string
+	credentials Credentials
 }
 
 // SetParameter adds a parameter to the request
@@ -28,6 +29,11 @@ func (request Request) Parameters() map[string]string {
 	return request.parameters
 }
 
+// Operation returns the name of the requested operation
+func (request Request) Operation() string {
+	return request.parameters["Operation"]
+}
+
 // QueryString returns the query string of the request
 func (request Request) QueryString() string {
 	var queryString string
@@ -64,3 +70,14 @@ func (request Request) SignedURL() (string, error) {
 
 	return fmt.Sprintf("%s&Signature=%s", request.URL(), url.QueryEscape(request.signature)), nil
 }
+
+// Region returns the region of the request endpoint
+func (request Request) Region() string {
+	for region, endpoint := range Endpoints {
+		if endpoint == request.endpoint {
+			return region
+		}
+	}
+
+	return ""
+}
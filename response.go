@@ -3,124 +3,168 @@ package amazonpa
 // Response describes the generic API Response
 type Response struct {
 	OperationRequest struct {
-		RequestID             string     `xml:"RequestId"`
-		Arguments             []Argument `xml:"Arguments>Argument"`
-		RequestProcessingTime float64
-	}
+		RequestID             string     `xml:"RequestId" json:"requestId"`
+		Arguments             []Argument `xml:"Arguments>Argument" json:"arguments,omitempty"`
+		RequestProcessingTime float64    `json:"requestProcessingTime"`
+	} `json:"operationRequest"`
 }
 
 // Argument todo
 type Argument struct {
-	Name  string `xml:"Name,attr"`
-	Value string `xml:"Value,attr"`
+	Name  string `xml:"Name,attr" json:"name"`
+	Value string `xml:"Value,attr" json:"value"`
 }
 
 // Image todo
 type Image struct {
-	URL    string
-	Height uint16
-	Width  uint16
+	URL    string `json:"url"`
+	Height uint16 `json:"height"`
+	Width  uint16 `json:"width"`
 }
 
 // Price describes the product price as
 // Amount of cents in CurrencyCode
 type Price struct {
-	Amount         uint
-	CurrencyCode   string
-	FormattedPrice string
+	Amount         uint   `json:"amount"`
+	CurrencyCode   string `json:"currencyCode"`
+	FormattedPrice string `json:"formattedPrice"`
 }
 
 type TopSeller struct {
-	ASIN  string
-	Title string
+	ASIN  string `json:"asin"`
+	Title string `json:"title"`
+}
+
+// TopSellers is the list of top sellers of a browse node
+type TopSellers struct {
+	TopSeller []TopSeller
+}
+
+// NewRelease is a recently released item of a browse node
+type NewRelease struct {
+	ASIN  string `json:"asin"`
+	Title string `json:"title"`
+}
+
+// NewReleases is the list of new releases of a browse node
+type NewReleases struct {
+	NewRelease []NewRelease
+}
+
+// Types of the top item sets of a browse node
+const (
+	TopItemSetTopSellers    = "TopSellers"
+	TopItemSetNewReleases   = "NewReleases"
+	TopItemSetMostGifted    = "MostGifted"
+	TopItemSetMostWishedFor = "MostWishedFor"
+)
+
+// TopItem is an item of a TopItemSet
+type TopItem struct {
+	ASIN          string   `json:"asin"`
+	Title         string   `json:"title"`
+	DetailPageURL string   `json:"detailPageUrl,omitempty"`
+	ProductGroup  string   `json:"productGroup,omitempty"`
+	Authors       []string `xml:"Author" json:"authors,omitempty"`
+	Actors        []string `xml:"Actor" json:"actors,omitempty"`
+}
+
+// TopItemSet is a list of top items of a browse node, as returned by the
+// TopSellers, NewReleases, MostGifted and MostWishedFor response groups
+type TopItemSet struct {
+	Type     string    `json:"type"`
+	TopItems []TopItem `xml:"TopItem" json:"topItems"`
 }
 
 // Item represents a product returned by the API
 type Item struct {
-	ASIN             string
-	URL              string
-	DetailPageURL    string
-	ItemAttributes   *ItemAttributes
-	OfferSummary     OfferSummary
-	Offers           Offers
-	SalesRank        int
-	SmallImage       *Image
-	MediumImage      *Image
-	LargeImage       *Image
-	ImageSets        *ImageSets
-	EditorialReviews EditorialReviews
-	BrowseNodes      struct {
-		BrowseNode []BrowseNode
-	}
+	ASIN             string           `json:"asin"`
+	URL              string           `json:"url,omitempty"`
+	DetailPageURL    string           `json:"detailPageUrl,omitempty"`
+	ItemAttributes   *ItemAttributes  `json:"itemAttributes,omitempty"`
+	OfferSummary     OfferSummary     `json:"offerSummary,omitzero"`
+	Offers           Offers           `json:"offers,omitzero"`
+	SalesRank        int              `json:"salesRank,omitempty"`
+	SmallImage       *Image           `json:"smallImage,omitempty"`
+	MediumImage      *Image           `json:"mediumImage,omitempty"`
+	LargeImage       *Image           `json:"largeImage,omitempty"`
+	ImageSets        *ImageSets       `json:"imageSets,omitempty"`
+	EditorialReviews EditorialReviews `json:"editorialReview,omitzero"`
+	BrowseNodes      BrowseNodes      `json:"browseNodes,omitzero"`
 }
 
 // BrowseNode represents a browse node returned by API
 type BrowseNode struct {
-	BrowseNodeID string `xml:"BrowseNodeId"`
-	Name         string
-	TopSellers   struct {
-		TopSeller []TopSeller
-	}
-	Ancestors struct {
-		BrowseNode []BrowseNode
-	}
+	BrowseNodeID   string       `xml:"BrowseNodeId" json:"browseNodeId"`
+	Name           string       `json:"name"`
+	IsCategoryRoot bool         `json:"isCategoryRoot,omitempty"`
+	TopSellers     TopSellers   `json:"topSellers,omitzero"`
+	NewReleases    NewReleases  `json:"newReleases,omitzero"`
+	TopItemSets    []TopItemSet `xml:"TopItemSet" json:"topItemSets,omitempty"`
+	Ancestors      BrowseNodes  `json:"ancestors,omitzero"`
+	Children       BrowseNodes  `json:"children,omitzero"`
+}
+
+// BrowseNodes is a list of browse nodes
+type BrowseNodes struct {
+	BrowseNode []BrowseNode
 }
 
 // ItemAttributes response group
 type ItemAttributes struct {
-	Author		string
-	Binding         string
-	Brand           string
-	Color           string
-	EAN             string
-	Creator         string
-	Title           string
-	ListPrice       Price
-	Manufacturer    string
-	Publisher       string
-	NumberOfItems   int
-	PackageQuantity int
-	Feature         string
-	Model           string
-	ProductGroup    string
-	ReleaseDate     string
-	Studio          string
-	Warranty        string
-	Size            string
-	UPC             string
+	Author          string `json:"author,omitempty"`
+	Binding         string `json:"binding,omitempty"`
+	Brand           string `json:"brand,omitempty"`
+	Color           string `json:"color,omitempty"`
+	EAN             string `json:"ean,omitempty"`
+	Creator         string `json:"creator,omitempty"`
+	Title           string `json:"title,omitempty"`
+	ListPrice       Price  `json:"listPrice,omitzero"`
+	Manufacturer    string `json:"manufacturer,omitempty"`
+	Publisher       string `json:"publisher,omitempty"`
+	NumberOfItems   int    `json:"numberOfItems,omitempty"`
+	PackageQuantity int    `json:"packageQuantity,omitempty"`
+	Feature         string `json:"feature,omitempty"`
+	Model           string `json:"model,omitempty"`
+	ProductGroup    string `json:"productGroup,omitempty"`
+	ReleaseDate     string `json:"releaseDate,omitempty"`
+	Studio          string `json:"studio,omitempty"`
+	Warranty        string `json:"warranty,omitempty"`
+	Size            string `json:"size,omitempty"`
+	UPC             string `json:"upc,omitempty"`
 }
 
 // Offer response attribute
 type Offer struct {
-	Condition       string `xml:"OfferAttributes>Condition"`
-	ID              string `xml:"OfferListing>OfferListingId"`
-	Price           Price  `xml:"OfferListing>Price"`
-	PercentageSaved uint   `xml:"OfferListing>PercentageSaved"`
-	Availability    string `xml:"OfferListing>Availability"`
+	Condition       string `xml:"OfferAttributes>Condition" json:"condition"`
+	ID              string `xml:"OfferListing>OfferListingId" json:"id"`
+	Price           Price  `xml:"OfferListing>Price" json:"price"`
+	PercentageSaved uint   `xml:"OfferListing>PercentageSaved" json:"percentageSaved,omitempty"`
+	Availability    string `xml:"OfferListing>Availability" json:"availability,omitempty"`
 }
 
 // Offers response group
 type Offers struct {
-	TotalOffers     int
-	TotalOfferPages int
-	MoreOffersURL   string  `xml:"MoreOffersUrl"`
-	Offers          []Offer `xml:"Offer"`
+	TotalOffers     int     `json:"totalOffers"`
+	TotalOfferPages int     `json:"totalOfferPages"`
+	MoreOffersURL   string  `xml:"MoreOffersUrl" json:"moreOffersUrl,omitempty"`
+	Offers          []Offer `xml:"Offer" json:"offers,omitempty"`
 }
 
 // OfferSummary response group
 type OfferSummary struct {
-	LowestNewPrice   Price
-	LowerUsedPrice   Price
-	TotalNew         int
-	TotalUsed        int
-	TotalCollectible int
-	TotalRefurbished int
+	LowestNewPrice   Price `json:"lowestNewPrice,omitzero"`
+	LowerUsedPrice   Price `json:"lowerUsedPrice,omitzero"`
+	TotalNew         int   `json:"totalNew"`
+	TotalUsed        int   `json:"totalUsed"`
+	TotalCollectible int   `json:"totalCollectible"`
+	TotalRefurbished int   `json:"totalRefurbished"`
 }
 
 // EditorialReview response attribute
 type EditorialReview struct {
-	Source  string
-	Content string
+	Source  string `json:"source"`
+	Content string `json:"content"`
 }
 
 // EditorialReviews response group
@@ -130,16 +174,16 @@ type EditorialReviews struct {
 
 // BrowseNodeLookupRequest is the confirmation of a BrowseNodeInfo request
 type BrowseNodeLookupRequest struct {
-	BrowseNodeId  string
-	ResponseGroup string
+	BrowseNodeId  string `json:"browseNodeId"`
+	ResponseGroup string `json:"responseGroup"`
 }
 
 // ItemLookupRequest is the confirmation of a ItemLookup request
 type ItemLookupRequest struct {
-	IDType        string `xml:"IdType"`
-	ItemID        string `xml:"ItemId"`
-	ResponseGroup string `xml:"ResponseGroup"`
-	VariationPage string
+	IDType        string `xml:"IdType" json:"idType"`
+	ItemID        string `xml:"ItemId" json:"itemId"`
+	ResponseGroup string `xml:"ResponseGroup" json:"responseGroup"`
+	VariationPage string `json:"variationPage,omitempty"`
 }
 
 // ItemLookupResponse describes the API response for the ItemLookup operation
@@ -147,43 +191,43 @@ type ItemLookupResponse struct {
 	Response
 	Items struct {
 		Request struct {
-			IsValid           bool
-			ItemLookupRequest ItemLookupRequest
-		}
-		Item Item `xml:"Item"`
-	}
+			IsValid           bool              `json:"isValid"`
+			ItemLookupRequest ItemLookupRequest `json:"itemLookupRequest"`
+		} `json:"request"`
+		Item Item `xml:"Item" json:"item"`
+	} `json:"items"`
 }
 
 // ItemSearchRequest is the confirmation of a ItemSearch request
 type ItemSearchRequest struct {
-	Keywords      string `xml:"Keywords"`
-	SearchIndex   string `xml:"SearchIndex"`
-	ResponseGroup string `xml:"ResponseGroup"`
+	Keywords      string `xml:"Keywords" json:"keywords"`
+	SearchIndex   string `xml:"SearchIndex" json:"searchIndex"`
+	ResponseGroup string `xml:"ResponseGroup" json:"responseGroup"`
 }
 
 type ItemSearchResponse struct {
 	Response
 	Items struct {
 		Request struct {
-			IsValid           bool
-			ItemSearchRequest ItemSearchRequest
-		}
-		Items                []Item `xml:"Item"`
-		TotalResult          int
-		TotalPages           int
-		MoreSearchResultsUrl string
-	}
+			IsValid           bool              `json:"isValid"`
+			ItemSearchRequest ItemSearchRequest `json:"itemSearchRequest"`
+		} `json:"request"`
+		Items                []Item `xml:"Item" json:"items"`
+		TotalResult          int    `json:"totalResults"`
+		TotalPages           int    `json:"totalPages"`
+		MoreSearchResultsUrl string `json:"moreSearchResultsUrl,omitempty"`
+	} `json:"items"`
 }
 
 type BrowseNodeLookupResponse struct {
 	Response
 	BrowseNodes struct {
 		Request struct {
-			IsValid                 bool
-			BrowseNodeLookupRequest BrowseNodeLookupRequest
-		}
-		BrowseNode BrowseNode
-	}
+			IsValid                 bool                    `json:"isValid"`
+			BrowseNodeLookupRequest BrowseNodeLookupRequest `json:"browseNodeLookupRequest"`
+		} `json:"request"`
+		BrowseNode BrowseNode `json:"browseNode"`
+	} `json:"browseNodes"`
 }
 
 type ImageSets struct {
@@ -192,11 +236,11 @@ type ImageSets struct {
 
 type ImageSet struct {
 	//Category string `xml:"Category,attr"`
-	Category       string `xml:",attr"`
-	SwatchImage    *Image
-	SmallImage     *Image
-	ThumbnailImage *Image
-	TinyImage      *Image
-	MediumImage    *Image
-	LargeImage     *Image
+	Category       string `xml:",attr" json:"category"`
+	SwatchImage    *Image `json:"swatchImage,omitempty"`
+	SmallImage     *Image `json:"smallImage,omitempty"`
+	ThumbnailImage *Image `json:"thumbnailImage,omitempty"`
+	TinyImage      *Image `json:"tinyImage,omitempty"`
+	MediumImage    *Image `json:"mediumImage,omitempty"`
+	LargeImage     *Image `json:"largeImage,omitempty"`
 }
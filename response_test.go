@@ -1,6 +1,7 @@
 package amazonpa
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"io/ioutil"
 	"testing"
@@ -88,3 +89,103 @@ func TestParseItemLookupResponse(t *testing.T) {
 	assertEqualStr(t, response.Items.Item.BrowseNodes.BrowseNode[0].Ancestors.BrowseNode[0].BrowseNodeID, "3119756031", "Bad Ancestors/BrowseNodeID")
 	assertEqualStr(t, response.Items.Item.BrowseNodes.BrowseNode[0].Ancestors.BrowseNode[0].Name, "Rubinetti da cucina", "Bad Ancestors/Name")
 }
+
+func TestItemJSON(t *testing.T) {
+	responseData, err := ioutil.ReadFile("testdata/itemlookup_response.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response ItemLookupResponse
+	xml.Unmarshal(responseData, &response)
+
+	data, err := json.Marshal(response.Items.Item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+
+	assertEqualStr(t, fields["asin"].(string), "B003TGG2EA", "Bad asin")
+	if _, ok := fields["BrowseNodes"]; ok {
+		t.Error("JSON must use the schema field names")
+	}
+
+	browseNodes, ok := fields["browseNodes"].([]interface{})
+	if !ok {
+		t.Fatal("browseNodes must be flattened to an array")
+	}
+	ancestors, ok := browseNodes[0].(map[string]interface{})["ancestors"].([]interface{})
+	if !ok || len(ancestors) == 0 {
+		t.Error("ancestors must be flattened to an array")
+	}
+	if _, ok := fields["imageSets"].([]interface{}); !ok {
+		t.Error("imageSets must be flattened to an array")
+	}
+
+	empty, _ := json.Marshal(Item{ASIN: "B003TGG2EA"})
+	assertEqualStr(t, string(empty), `{"asin":"B003TGG2EA"}`, "Absent response groups must be omitted")
+
+	var item Item
+	if err := json.Unmarshal(data, &item); err != nil {
+		t.Fatal(err)
+	}
+	assertEqualStr(t, item.BrowseNodes.BrowseNode[0].Ancestors.BrowseNode[0].Name, "Rubinetti da cucina", "Bad JSON round trip")
+	assertEqualInt(t, int(item.ItemAttributes.ListPrice.Amount), 18500, "Bad JSON round trip")
+}
+
+func TestBrowseNodePath(t *testing.T) {
+	responseData, err := ioutil.ReadFile("testdata/itemlookup_response.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response ItemLookupResponse
+	xml.Unmarshal(responseData, &response)
+
+	node := response.Items.Item.BrowseNodes.BrowseNode[0]
+	path := node.Path()
+
+	assertEqualInt(t, len(path), 6, "Bad Path length")
+	assertEqualStr(t, path[0].BrowseNodeID, "2454160031", "Bad Path root")
+	assertEqualStr(t, path[5].BrowseNodeID, "3120323031", "Bad Path leaf")
+	assertEqualBool(t, path[1].IsCategoryRoot, true, "Bad IsCategoryRoot")
+	assertEqualStr(t, node.Root().Name, "Fai da te", "Bad Root")
+	assertEqualStr(t, node.Breadcrumb(" > "), "Fai da te > Attrezzature per cucine e bagni > Impianti per la cucina > Rubinetti da cucina > Rubinetti per lavelli da cucina", "Bad Breadcrumb")
+
+	assertEqualStr(t, BrowseNode{Name: "Books"}.Breadcrumb("/"), "Books", "Bad Breadcrumb without ancestors")
+
+	categoryPath := response.Items.Item.CategoryPath()
+	assertEqualStr(t, categoryPath[len(categoryPath)-1].BrowseNodeID, "3120323031", "Bad CategoryPath")
+
+	if (Item{}).CategoryPath() != nil {
+		t.Error("CategoryPath must be nil without browse nodes")
+	}
+}
+
+func TestParseBrowseNodeLookupTopItems(t *testing.T) {
+	responseData, err := ioutil.ReadFile("testdata/browsenodelookup_response.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response BrowseNodeLookupResponse
+	xml.Unmarshal(responseData, &response)
+
+	node := response.BrowseNodes.BrowseNode
+	assertEqualBool(t, response.BrowseNodes.Request.IsValid, true, "Bad IsValid")
+	assertEqualStr(t, node.Name, "Programming Languages", "Bad Name")
+	assertEqualInt(t, len(node.TopSellers.TopSeller), 2, "Bad TopSellers")
+	assertEqualStr(t, node.NewReleases.NewRelease[0].ASIN, "1718503105", "Bad NewReleases")
+
+	topSellers := node.TopItems(TopItemSetTopSellers)
+	assertEqualInt(t, len(topSellers), 2, "Bad TopSellers set")
+	assertEqualStr(t, topSellers[0].DetailPageURL, "https://www.amazon.com/dp/0134190440", "Bad TopItem/DetailPageURL")
+	assertEqualInt(t, len(topSellers[0].Authors), 2, "Bad TopItem/Author")
+
+	assertEqualStr(t, node.TopItems(TopItemSetNewReleases)[0].Title, "The Rust Programming Language", "Bad NewReleases set")
+	assertEqualStr(t, node.TopItems(TopItemSetMostGifted)[0].ASIN, "0262033844", "Bad MostGifted set")
+	assertEqualStr(t, node.TopItems(TopItemSetMostWishedFor)[0].Title, "Design Patterns", "Bad MostWishedFor set")
+
+	if node.TopItems("Unknown") != nil {
+		t.Error("Missing sets must be nil")
+	}
+}
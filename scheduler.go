@@ -0,0 +1,279 @@
+package amazonpa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Priority is the scheduling class of a request
+type Priority int
+
+// Priority classes, from the lowest to the highest
+const (
+	PriorityBatch Priority = iota
+	PriorityNormal
+	PriorityInteractive
+)
+
+// priorities is the number of priority classes
+const priorities = 3
+
+// valid reports whether the priority is one of the priority classes
+func (priority Priority) valid() bool {
+	return priority >= 0 && priority < priorities
+}
+
+func (priority Priority) String() string {
+	switch priority {
+	case PriorityBatch:
+		return "batch"
+	case PriorityNormal:
+		return "normal"
+	case PriorityInteractive:
+		return "interactive"
+	}
+
+	return fmt.Sprintf("Priority(%d)", int(priority))
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a context whose requests are scheduled with the
+// given priority
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext returns the priority of the requests of the context,
+// PriorityNormal by default
+func PriorityFromContext(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok && priority.valid() {
+		return priority
+	}
+
+	return PriorityNormal
+}
+
+// ErrQueueFull is matched by the QueueFullError returned when a request
+// cannot be queued by the scheduler
+var ErrQueueFull = errors.New("amazonpa: scheduler queue is full")
+
+// QueueFullError is returned when the queue of a priority class is full
+type QueueFullError struct {
+	Priority Priority
+	Limit    int
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("amazonpa: %s queue is full (%d requests)", e.Priority, e.Limit)
+}
+
+// Is makes errors.Is(err, ErrQueueFull) report true
+func (e *QueueFullError) Is(target error) bool {
+	return target == ErrQueueFull
+}
+
+// waiter is a request waiting for its turn
+type waiter struct {
+	ready chan struct{}
+}
+
+// priorityQueue is the queue of a priority class
+type priorityQueue struct {
+	waiters []*waiter
+	limit   int
+	weight  int
+	pass    float64
+}
+
+// Scheduler spaces out the requests to a maximum rate like the rate limit
+// of the client, but serves the queued requests by priority. The classes
+// share the rate with weighted fair queuing, so that batch requests are
+// slowed down by the interactive ones without being starved. A Scheduler
+// can be shared by several clients.
+type Scheduler struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+	running  bool
+	pass     float64
+	queues   [priorities]priorityQueue
+
+	// Clock of the scheduler, replaced in the tests
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewScheduler returns a scheduler for requestsPerSecond. By default the
+// interactive, normal and batch classes get 8, 4 and 1 shares of the rate
+// and their queues are not limited. Like with SetRateLimit, a non-positive
+// requestsPerSecond does not limit the rate: the requests are then only
+// served in order of priority.
+func NewScheduler(requestsPerSecond float64) *Scheduler {
+	scheduler := &Scheduler{
+		now:   time.Now,
+		sleep: time.Sleep,
+	}
+
+	if requestsPerSecond > 0 {
+		scheduler.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+
+	scheduler.queues[PriorityInteractive].weight = 8
+	scheduler.queues[PriorityNormal].weight = 4
+	scheduler.queues[PriorityBatch].weight = 1
+
+	return scheduler
+}
+
+// SetWeight sets the share of the rate of a priority class, when requests
+// of several classes are queued. Unknown priorities are ignored.
+func (scheduler *Scheduler) SetWeight(priority Priority, weight int) {
+	if !priority.valid() {
+		return
+	}
+
+	if weight < 1 {
+		weight = 1
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduler.queues[priority].weight = weight
+}
+
+// SetQueueLimit limits the requests waiting in the queue of a priority
+// class. Over the limit the requests fail with a QueueFullError. A
+// non-positive value removes the limit. Unknown priorities are ignored.
+func (scheduler *Scheduler) SetQueueLimit(priority Priority, limit int) {
+	if !priority.valid() {
+		return
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduler.queues[priority].limit = limit
+}
+
+// QueueDepth returns the number of requests waiting in the queue of a
+// priority class
+func (scheduler *Scheduler) QueueDepth(priority Priority) int {
+	if !priority.valid() {
+		return 0
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	return len(scheduler.queues[priority].waiters)
+}
+
+// Wait blocks until it is the turn of a request with the priority of the
+// context, or the context is done
+func (scheduler *Scheduler) Wait(ctx context.Context) error {
+	priority := PriorityFromContext(ctx)
+
+	scheduler.mutex.Lock()
+
+	queue := &scheduler.queues[priority]
+	if queue.limit > 0 && len(queue.waiters) >= queue.limit {
+		scheduler.mutex.Unlock()
+		return &QueueFullError{Priority: priority, Limit: queue.limit}
+	}
+
+	// A class becoming active does not get credit for the time it was idle
+	if len(queue.waiters) == 0 && queue.pass < scheduler.pass {
+		queue.pass = scheduler.pass
+	}
+
+	w := &waiter{ready: make(chan struct{})}
+	queue.waiters = append(queue.waiters, w)
+
+	if !scheduler.running {
+		scheduler.running = true
+		go scheduler.dispatch()
+	}
+
+	scheduler.mutex.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	for i, queued := range queue.waiters {
+		if queued == w {
+			queue.waiters = append(queue.waiters[:i], queue.waiters[i+1:]...)
+			return ctx.Err()
+		}
+	}
+
+	// The turn was given in the meantime and is lost
+	return ctx.Err()
+}
+
+// dispatch gives the turns to the queued requests until the queues are
+// empty
+func (scheduler *Scheduler) dispatch() {
+	for {
+		scheduler.mutex.Lock()
+		delay := scheduler.next.Sub(scheduler.now())
+		scheduler.mutex.Unlock()
+
+		if delay > 0 {
+			scheduler.sleep(delay)
+		}
+
+		scheduler.mutex.Lock()
+
+		queue := scheduler.pick()
+		if queue == nil {
+			scheduler.running = false
+			scheduler.mutex.Unlock()
+			return
+		}
+
+		w := queue.waiters[0]
+		queue.waiters = queue.waiters[1:]
+
+		scheduler.pass = queue.pass
+		queue.pass += 1 / float64(queue.weight)
+
+		now := scheduler.now()
+		if scheduler.next.Before(now) {
+			scheduler.next = now
+		}
+		scheduler.next = scheduler.next.Add(scheduler.interval)
+
+		close(w.ready)
+		scheduler.mutex.Unlock()
+	}
+}
+
+// pick returns the queue to serve, which is the non-empty queue with the
+// lowest pass, preferring the higher priorities on ties
+func (scheduler *Scheduler) pick() *priorityQueue {
+	var picked *priorityQueue
+
+	for priority := priorities - 1; priority >= 0; priority-- {
+		queue := &scheduler.queues[priority]
+		if len(queue.waiters) == 0 {
+			continue
+		}
+
+		if picked == nil || queue.pass < picked.pass {
+			picked = queue
+		}
+	}
+
+	return picked
+}
@@ -0,0 +1,174 @@
+package amazonpa
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// newGatedScheduler returns a scheduler with a frozen clock, whose turns
+// after the first one are only given when a token is sent to the returned
+// channel. Closing the channel releases the scheduler.
+func newGatedScheduler() (*Scheduler, chan struct{}) {
+	tokens := make(chan struct{})
+	now := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	scheduler := NewScheduler(1)
+	scheduler.now = func() time.Time { return now }
+	scheduler.sleep = func(time.Duration) { <-tokens }
+
+	return scheduler, tokens
+}
+
+// waitForDepth waits until the queue of the priority holds depth requests
+func waitForDepth(scheduler *Scheduler, priority Priority, depth int) {
+	for scheduler.QueueDepth(priority) != depth {
+		runtime.Gosched()
+	}
+}
+
+func TestSchedulerPriorities(t *testing.T) {
+	scheduler, tokens := newGatedScheduler()
+	defer close(tokens)
+
+	// The first request is served at once
+	if err := scheduler.Wait(WithPriority(context.Background(), PriorityBatch)); err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan Priority)
+
+	enqueue := func(priority Priority, count int) {
+		for i := 0; i < count; i++ {
+			go func() {
+				if err := scheduler.Wait(WithPriority(context.Background(), priority)); err != nil {
+					t.Error(err)
+				}
+				served <- priority
+			}()
+		}
+		waitForDepth(scheduler, priority, count)
+	}
+
+	enqueue(PriorityBatch, 3)
+	enqueue(PriorityInteractive, 2)
+
+	expected := []Priority{PriorityInteractive, PriorityInteractive, PriorityBatch, PriorityBatch, PriorityBatch}
+	for i, priority := range expected {
+		tokens <- struct{}{}
+		if got := <-served; got != priority {
+			t.Fatalf("Turn %d given to %s instead of %s", i, got, priority)
+		}
+	}
+}
+
+func TestSchedulerFairness(t *testing.T) {
+	scheduler, tokens := newGatedScheduler()
+	defer close(tokens)
+	scheduler.SetWeight(PriorityInteractive, 2)
+
+	if err := scheduler.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan Priority)
+
+	for _, priority := range []Priority{PriorityBatch, PriorityInteractive} {
+		for i := 0; i < 6; i++ {
+			go func(priority Priority) {
+				scheduler.Wait(WithPriority(context.Background(), priority))
+				served <- priority
+			}(priority)
+		}
+		waitForDepth(scheduler, priority, 6)
+	}
+
+	// With 2:1 shares, batch requests are served every third turn
+	batches := 0
+	for i := 0; i < 6; i++ {
+		tokens <- struct{}{}
+		if <-served == PriorityBatch {
+			batches++
+		}
+	}
+	assertEqualInt(t, batches, 2, "Bad number of batch turns")
+
+	for i := 0; i < 6; i++ {
+		tokens <- struct{}{}
+		<-served
+	}
+}
+
+func TestSchedulerQueueLimit(t *testing.T) {
+	scheduler, tokens := newGatedScheduler()
+	defer close(tokens)
+	scheduler.SetQueueLimit(PriorityBatch, 1)
+
+	// Unknown priorities are ignored
+	scheduler.SetQueueLimit(Priority(7), 1)
+	scheduler.SetWeight(Priority(-1), 1)
+
+	batch := WithPriority(context.Background(), PriorityBatch)
+
+	if err := scheduler.Wait(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(batch)
+	done := make(chan error)
+	go func() { done <- scheduler.Wait(ctx) }()
+	waitForDepth(scheduler, PriorityBatch, 1)
+
+	err := scheduler.Wait(batch)
+	var queueFull *QueueFullError
+	if !errors.As(err, &queueFull) || !errors.Is(err, ErrQueueFull) || queueFull.Priority != PriorityBatch {
+		t.Fatalf("Expected a QueueFullError, got %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	assertEqualInt(t, scheduler.QueueDepth(PriorityBatch), 0, "Cancelled request must leave the queue")
+
+	// Other classes are not limited
+	normal := make(chan error)
+	for i := 0; i < 2; i++ {
+		go func() { normal <- scheduler.Wait(context.Background()) }()
+	}
+	waitForDepth(scheduler, PriorityNormal, 2)
+
+	for i := 0; i < 2; i++ {
+		tokens <- struct{}{}
+		if err := <-normal; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestSchedulerUnlimited(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		scheduler := NewScheduler(rate)
+		scheduler.sleep = func(delay time.Duration) {
+			t.Errorf("Scheduler with rate %v waited %s", rate, delay)
+		}
+
+		for i := 0; i < 3; i++ {
+			if err := scheduler.Wait(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestClientScheduler(t *testing.T) {
+	client := newFixtureClient("testdata/itemlookup_response.xml")
+	client.SetScheduler(NewScheduler(1000))
+
+	ctx := WithPriority(context.Background(), PriorityInteractive)
+	if _, err := client.ItemLookupContext(ctx, ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}}); err != nil {
+		t.Fatal(err)
+	}
+}
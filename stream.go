@@ -0,0 +1,184 @@
+package amazonpa
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// ItemHandler is called for every item decoded from a streamed response.
+// Returning an error stops the decoding.
+type ItemHandler func(item Item) error
+
+// StreamItems performs the request and decodes the returned items one at a
+// time directly from the response body, instead of reading the whole
+// response in memory. If raw is not nil, a copy of the response body is
+// written to it for debugging.
+func (client Client) StreamItems(request *Request, raw io.Writer, handler ItemHandler) error {
+	return client.StreamItemsContext(context.Background(), request, raw, handler)
+}
+
+// StreamItemsContext streams the items of the response like StreamItems,
+// giving up when the context is done
+func (client Client) StreamItemsContext(ctx context.Context, request *Request, raw io.Writer, handler ItemHandler) error {
+
+	var exchange Exchange
+
+	exchange.Request = request
+	exchange.stream = func(httpResponse *http.Response) error {
+		return decodeItems(request, httpResponse, raw, func(item Item) error {
+			exchange.items++
+			return handler(item)
+		})
+	}
+
+	return client.do(ctx, &exchange)
+}
+
+// decodeItems decodes the items of the response body, calling the handler
+// for each of them
+func decodeItems(request *Request, httpResponse *http.Response, raw io.Writer, handler ItemHandler) error {
+
+	operation := request.Operation()
+	contentType := httpResponse.Header.Get("Content-Type")
+
+	// Keep the beginning of the body for the decode errors
+	excerpt := &excerptWriter{}
+
+	var body io.Reader = io.TeeReader(httpResponse.Body, excerpt)
+	if raw != nil {
+		body = io.TeeReader(body, raw)
+	}
+
+	if !isXMLContentType(contentType) {
+		io.CopyN(ioutil.Discard, body, excerptLength)
+		return newDecodeError(operation, contentType, excerpt.Bytes(), fmt.Errorf("unexpected content type %q", contentType))
+	}
+
+	decoder := xml.NewDecoder(body)
+
+	// Names of the open elements, used to pick the Items>Item elements only
+	var path []string
+
+	for {
+		token, err := decoder.Token()
+
+		if err == io.EOF && path != nil {
+			return nil
+		}
+
+		if err == io.EOF {
+			return newDecodeError(operation, contentType, excerpt.Bytes(), io.ErrUnexpectedEOF)
+		}
+
+		if err != nil {
+			return newDecodeError(operation, contentType, excerpt.Bytes(), err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if path == nil && strings.HasSuffix(element.Name.Local, "ErrorResponse") {
+				return decodeErrorResponse(operation, httpResponse.StatusCode, contentType, excerpt.Bytes(), decoder, element)
+			}
+
+			parent := ""
+			if len(path) > 0 {
+				parent = path[len(path)-1]
+			}
+
+			if parent != "Items" {
+				path = append(path, element.Name.Local)
+				continue
+			}
+
+			switch element.Name.Local {
+			case "Request":
+				var itemsRequest struct{ IsValid bool }
+				if err := decoder.DecodeElement(&itemsRequest, &element); err != nil {
+					return newDecodeError(operation, contentType, excerpt.Bytes(), err)
+				}
+
+				if itemsRequest.IsValid != true {
+					return errors.New("amazonpa: request is invalid")
+				}
+			case "Item":
+				var item Item
+				if err := decoder.DecodeElement(&item, &element); err != nil {
+					return newDecodeError(operation, contentType, excerpt.Bytes(), err)
+				}
+
+				if err := handler(item); err != nil {
+					return err
+				}
+			default:
+				path = append(path, element.Name.Local)
+			}
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		}
+	}
+}
+
+// ItemLookupStream performs an ItemLookup request, passing the returned
+// items to the handler as they are decoded
+func (client Client) ItemLookupStream(query ItemLookupQuery, handler ItemHandler) error {
+	return client.ItemLookupStreamContext(context.Background(), query, handler)
+}
+
+// ItemLookupStreamContext streams the items of an ItemLookup request like
+// ItemLookupStream, giving up when the context is done
+func (client Client) ItemLookupStreamContext(ctx context.Context, query ItemLookupQuery, handler ItemHandler) error {
+
+	request := client.NewRequest("ItemLookup")
+
+	for key, value := range query.Parameters() {
+		request.SetParameter(key, value)
+	}
+
+	return client.StreamItemsContext(ctx, request, nil, handler)
+}
+
+// ItemSearchStream performs an ItemSearch request, passing the returned
+// items to the handler as they are decoded
+func (client Client) ItemSearchStream(query ItemSearchQuery, handler ItemHandler) error {
+	return client.ItemSearchStreamContext(context.Background(), query, handler)
+}
+
+// ItemSearchStreamContext streams the items of an ItemSearch request like
+// ItemSearchStream, giving up when the context is done
+func (client Client) ItemSearchStreamContext(ctx context.Context, query ItemSearchQuery, handler ItemHandler) error {
+
+	if err := query.Validate(client.config.Region); err != nil {
+		return err
+	}
+
+	request := client.NewRequest("ItemSearch")
+
+	for key, value := range query.Parameters() {
+		request.SetParameter(key, value)
+	}
+
+	return client.StreamItemsContext(ctx, request, nil, handler)
+}
+
+// excerptWriter keeps the first bytes written to it
+type excerptWriter struct {
+	bytes.Buffer
+}
+
+func (writer *excerptWriter) Write(p []byte) (int, error) {
+	if room := excerptLength - writer.Len(); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		writer.Buffer.Write(p[:room])
+	}
+
+	return len(p), nil
+}
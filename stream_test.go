@@ -0,0 +1,69 @@
+package amazonpa
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+func TestItemLookupStream(t *testing.T) {
+	client := newFixtureClient("testdata/itemlookup_response.xml")
+
+	var items []Item
+	err := client.ItemLookupStream(ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}}, func(item Item) error {
+		items = append(items, item)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqualInt(t, len(items), 1, "Bad number of streamed items")
+	assertEqualStr(t, items[0].ASIN, "B003TGG2EA", "Bad ASIN")
+	assertEqualStr(t, items[0].ItemAttributes.Brand, "Grohe", "Bad Brand")
+}
+
+func TestStreamItemsRawAndStop(t *testing.T) {
+	client := newFixtureClient("testdata/itemlookup_response.xml")
+	stop := errors.New("stop")
+
+	var raw bytes.Buffer
+	err := client.StreamItems(client.NewRequest("ItemLookup"), &raw, func(item Item) error {
+		return stop
+	})
+
+	if err != stop {
+		t.Errorf("Handler error must stop the stream, got %v", err)
+	}
+
+	expected, _ := ioutil.ReadFile("testdata/itemlookup_response.xml")
+	if !bytes.HasPrefix(expected, raw.Bytes()) || raw.Len() == 0 {
+		t.Error("Raw output must be a copy of the response body")
+	}
+}
+
+func TestStreamContextCanceled(t *testing.T) {
+	client := newFixtureClient("testdata/itemlookup_response.xml")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	handler := func(item Item) error {
+		t.Error("No item must be streamed with a canceled context")
+		return nil
+	}
+
+	if err := client.ItemSearchStreamContext(ctx, ItemSearchQuery{SearchIndex: SearchIndexAll, Keywords: "grohe"}, handler); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if _, err := client.LookupIdentifiersContext(ctx, []string{"B003TGG2EA", "9780306406157"}, ItemLookupQuery{}); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if _, err := client.HydrateTopItemsContext(ctx, []TopItem{{ASIN: "B003TGG2EA"}}, nil); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
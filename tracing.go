@@ -0,0 +1,90 @@
+package amazonpa
+
+import "context"
+
+// Tracer creates the spans of the API calls. It is a small subset of the
+// tracing libraries, such as OpenTelemetry, which can be plugged in with an
+// adapter.
+type Tracer interface {
+	// Start creates a span that is a child of the span of ctx, if any, and
+	// returns a context carrying it
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is an operation being traced
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Attributes set on the spans
+const (
+	AttributeOperation     = "amazonpa.operation"
+	AttributeMarketplace   = "amazonpa.marketplace"
+	AttributeResponseGroup = "amazonpa.response_group"
+	AttributeItemCount     = "amazonpa.item_count"
+	AttributeAttempts      = "amazonpa.attempts"
+	AttributeStatusCode    = "http.status_code"
+)
+
+// SetTracer sets the tracer creating a span around every API call. The
+// span wraps the middlewares, which can get it from the context with their
+// tracing library.
+func (client *Client) SetTracer(tracer Tracer) {
+	client.tracer = tracer
+}
+
+// startSpan starts the span of an exchange, if a tracer is set
+func (client Client) startSpan(ctx context.Context, exchange *Exchange) (context.Context, Span) {
+	if client.tracer == nil {
+		return ctx, nil
+	}
+
+	request := exchange.Request
+	ctx, span := client.tracer.Start(ctx, "amazonpa."+request.Operation())
+
+	span.SetAttribute(AttributeOperation, request.Operation())
+	span.SetAttribute(AttributeMarketplace, request.Region())
+
+	if responseGroup := request.Parameters()["ResponseGroup"]; responseGroup != "" {
+		span.SetAttribute(AttributeResponseGroup, responseGroup)
+	}
+
+	return ctx, span
+}
+
+// endSpan records the outcome of an exchange on its span
+func endSpan(span Span, exchange *Exchange, err error) {
+	if span == nil {
+		return
+	}
+
+	span.SetAttribute(AttributeAttempts, exchange.attempts)
+	span.SetAttribute(AttributeItemCount, exchange.itemCount())
+
+	if exchange.HTTPResponse != nil {
+		span.SetAttribute(AttributeStatusCode, exchange.HTTPResponse.StatusCode)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	span.End()
+}
+
+// itemCount returns the number of items of the response
+func (exchange *Exchange) itemCount() int {
+	switch result := exchange.Result.(type) {
+	case *ItemLookupResponse:
+		if result.Items.Item.ASIN != "" {
+			return 1
+		}
+		return 0
+	case *ItemSearchResponse:
+		return len(result.Items.Items)
+	}
+
+	return exchange.items
+}
@@ -0,0 +1,94 @@
+package amazonpa
+
+import (
+	"context"
+	"testing"
+)
+
+type spanKey struct{}
+
+// testSpan records its attributes
+type testSpan struct {
+	name       string
+	parent     *testSpan
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (span *testSpan) SetAttribute(key string, value interface{}) { span.attributes[key] = value }
+func (span *testSpan) RecordError(err error)                      { span.err = err }
+func (span *testSpan) End()                                       { span.ended = true }
+
+// testTracer keeps the started spans
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (tracer *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(spanKey{}).(*testSpan)
+	span := &testSpan{name: name, parent: parent, attributes: map[string]interface{}{}}
+	tracer.spans = append(tracer.spans, span)
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestTracing(t *testing.T) {
+	tracer := &testTracer{}
+
+	client := newFixtureClient("testdata/itemlookup_response.xml")
+	client.SetTracer(tracer)
+
+	var middlewareSpan *testSpan
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, exchange *Exchange) error {
+			middlewareSpan, _ = ctx.Value(spanKey{}).(*testSpan)
+			return next(ctx, exchange)
+		}
+	})
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+
+	query := ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}, ResponseGroups: []ResponseGroup{ResponseGroupLarge}}
+	if _, err := client.ItemLookupContext(ctx, query); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(tracer.spans))
+	}
+
+	span := tracer.spans[1]
+	assertEqualStr(t, span.name, "amazonpa.ItemLookup", "Bad span name")
+
+	if span.parent != parent.(*testSpan) {
+		t.Error("Span must be a child of the caller span")
+	}
+	if middlewareSpan != span {
+		t.Error("Middlewares must get the span in the context")
+	}
+	if !span.ended {
+		t.Error("Span must be ended")
+	}
+
+	assertEqualStr(t, span.attributes[AttributeMarketplace].(string), "US", "Bad marketplace")
+	assertEqualStr(t, span.attributes[AttributeResponseGroup].(string), "Large", "Bad response group")
+	assertEqualInt(t, span.attributes[AttributeItemCount].(int), 1, "Bad item count")
+	assertEqualInt(t, span.attributes[AttributeAttempts].(int), 1, "Bad attempts")
+	assertEqualInt(t, span.attributes[AttributeStatusCode].(int), 200, "Bad status code")
+}
+
+func TestTracingError(t *testing.T) {
+	tracer := &testTracer{}
+
+	client := newFixtureClientWithStatus("testdata/itemlookup_error_response.xml", 503, "text/xml")
+	client.SetTracer(tracer)
+
+	err := client.ItemLookupStream(ItemLookupQuery{ItemIDs: []string{"B003TGG2EA"}}, func(item Item) error {
+		return nil
+	})
+
+	if err == nil || tracer.spans[0].err != err {
+		t.Errorf("Span must record the error %v", err)
+	}
+}
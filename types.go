@@ -0,0 +1,222 @@
+package amazonpa
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SearchIndex is the name of a search index
+type SearchIndex string
+
+// Search indexes
+const (
+	SearchIndexAll                SearchIndex = "All"
+	SearchIndexApparel            SearchIndex = "Apparel"
+	SearchIndexAppliances         SearchIndex = "Appliances"
+	SearchIndexArtsAndCrafts      SearchIndex = "ArtsAndCrafts"
+	SearchIndexAutomotive         SearchIndex = "Automotive"
+	SearchIndexBaby               SearchIndex = "Baby"
+	SearchIndexBeauty             SearchIndex = "Beauty"
+	SearchIndexBlended            SearchIndex = "Blended"
+	SearchIndexBooks              SearchIndex = "Books"
+	SearchIndexClassical          SearchIndex = "Classical"
+	SearchIndexCollectibles       SearchIndex = "Collectibles"
+	SearchIndexCreditCards        SearchIndex = "CreditCards"
+	SearchIndexDigitalMusic       SearchIndex = "DigitalMusic"
+	SearchIndexDVD                SearchIndex = "DVD"
+	SearchIndexElectronics        SearchIndex = "Electronics"
+	SearchIndexFashion            SearchIndex = "Fashion"
+	SearchIndexFashionBaby        SearchIndex = "FashionBaby"
+	SearchIndexFashionBoys        SearchIndex = "FashionBoys"
+	SearchIndexFashionGirls       SearchIndex = "FashionGirls"
+	SearchIndexFashionMen         SearchIndex = "FashionMen"
+	SearchIndexFashionWomen       SearchIndex = "FashionWomen"
+	SearchIndexForeignBooks       SearchIndex = "ForeignBooks"
+	SearchIndexFurniture          SearchIndex = "Furniture"
+	SearchIndexGiftCards          SearchIndex = "GiftCards"
+	SearchIndexGrocery            SearchIndex = "Grocery"
+	SearchIndexHandmade           SearchIndex = "Handmade"
+	SearchIndexHealthPersonalCare SearchIndex = "HealthPersonalCare"
+	SearchIndexHobbies            SearchIndex = "Hobbies"
+	SearchIndexHome               SearchIndex = "Home"
+	SearchIndexHomeGarden         SearchIndex = "HomeGarden"
+	SearchIndexHomeImprovement    SearchIndex = "HomeImprovement"
+	SearchIndexIndustrial         SearchIndex = "Industrial"
+	SearchIndexJewelry            SearchIndex = "Jewelry"
+	SearchIndexKindleStore        SearchIndex = "KindleStore"
+	SearchIndexKitchen            SearchIndex = "Kitchen"
+	SearchIndexLawnAndGarden      SearchIndex = "LawnAndGarden"
+	SearchIndexLighting           SearchIndex = "Lighting"
+	SearchIndexLuggage            SearchIndex = "Luggage"
+	SearchIndexLuxuryBeauty       SearchIndex = "LuxuryBeauty"
+	SearchIndexMagazines          SearchIndex = "Magazines"
+	SearchIndexMerchants          SearchIndex = "Merchants"
+	SearchIndexMiscellaneous      SearchIndex = "Miscellaneous"
+	SearchIndexMobileApps         SearchIndex = "MobileApps"
+	SearchIndexMovies             SearchIndex = "Movies"
+	SearchIndexMP3Downloads       SearchIndex = "MP3Downloads"
+	SearchIndexMusic              SearchIndex = "Music"
+	SearchIndexMusicalInstruments SearchIndex = "MusicalInstruments"
+	SearchIndexOfficeProducts     SearchIndex = "OfficeProducts"
+	SearchIndexPantry             SearchIndex = "Pantry"
+	SearchIndexPCHardware         SearchIndex = "PCHardware"
+	SearchIndexPetSupplies        SearchIndex = "PetSupplies"
+	SearchIndexPhoto              SearchIndex = "Photo"
+	SearchIndexShoes              SearchIndex = "Shoes"
+	SearchIndexSoftware           SearchIndex = "Software"
+	SearchIndexSportingGoods      SearchIndex = "SportingGoods"
+	SearchIndexTools              SearchIndex = "Tools"
+	SearchIndexToys               SearchIndex = "Toys"
+	SearchIndexUnboxVideo         SearchIndex = "UnboxVideo"
+	SearchIndexVehicles           SearchIndex = "Vehicles"
+	SearchIndexVideo              SearchIndex = "Video"
+	SearchIndexVideoGames         SearchIndex = "VideoGames"
+	SearchIndexWatches            SearchIndex = "Watches"
+	SearchIndexWine               SearchIndex = "Wine"
+	SearchIndexWireless           SearchIndex = "Wireless"
+)
+
+// Sort is the sort order of the ItemSearch results. The accepted values
+// depend on the search index.
+type Sort string
+
+// Sort orders
+const (
+	SortRelevanceRank        Sort = "relevancerank"
+	SortSalesRank            Sort = "salesrank"
+	SortReviewRank           Sort = "reviewrank"
+	SortPopularityRank       Sort = "popularity-rank"
+	SortPSRank               Sort = "psrank"
+	SortPrice                Sort = "price"
+	SortPriceDesc            Sort = "-price"
+	SortPriceRank            Sort = "pricerank"
+	SortInversePriceRank     Sort = "inverse-pricerank"
+	SortTitleRank            Sort = "titlerank"
+	SortTitleRankDesc        Sort = "-titlerank"
+	SortArtistRank           Sort = "artistrank"
+	SortDateRank             Sort = "daterank"
+	SortLaunchDate           Sort = "launch-date"
+	SortLaunchDateDesc       Sort = "-launch-date"
+	SortPublicationDateDesc  Sort = "-publication_date"
+	SortUnitSalesDesc        Sort = "-unit-sales"
+	SortOriginalReleaseDate  Sort = "orig-rel-date"
+	SortReleaseDate          Sort = "release-date"
+	SortReleaseDateAsc       Sort = "releasedate"
+	SortReleaseDateDesc      Sort = "-releasedate"
+	SortVideoReleaseDateDesc Sort = "-video-release-date"
+)
+
+// Condition is the condition of the returned offers
+type Condition string
+
+// Offer conditions
+const (
+	ConditionAll         Condition = "All"
+	ConditionNew         Condition = "New"
+	ConditionUsed        Condition = "Used"
+	ConditionCollectible Condition = "Collectible"
+	ConditionRefurbished Condition = "Refurbished"
+)
+
+// IDType is the type of the item IDs of an ItemLookup request
+type IDType string
+
+// Item ID types
+const (
+	IDTypeASIN IDType = "ASIN"
+	IDTypeSKU  IDType = "SKU"
+	IDTypeUPC  IDType = "UPC"
+	IDTypeEAN  IDType = "EAN"
+	IDTypeISBN IDType = "ISBN"
+)
+
+// ResponseGroup selects the data returned by the API
+type ResponseGroup string
+
+// Response groups
+const (
+	ResponseGroupAccessories       ResponseGroup = "Accessories"
+	ResponseGroupAlternateVersions ResponseGroup = "AlternateVersions"
+	ResponseGroupBrowseNodeInfo    ResponseGroup = "BrowseNodeInfo"
+	ResponseGroupBrowseNodes       ResponseGroup = "BrowseNodes"
+	ResponseGroupEditorialReview   ResponseGroup = "EditorialReview"
+	ResponseGroupImages            ResponseGroup = "Images"
+	ResponseGroupItemAttributes    ResponseGroup = "ItemAttributes"
+	ResponseGroupItemIds           ResponseGroup = "ItemIds"
+	ResponseGroupLarge             ResponseGroup = "Large"
+	ResponseGroupMedium            ResponseGroup = "Medium"
+	ResponseGroupMostGifted        ResponseGroup = "MostGifted"
+	ResponseGroupMostWishedFor     ResponseGroup = "MostWishedFor"
+	ResponseGroupNewReleases       ResponseGroup = "NewReleases"
+	ResponseGroupOfferFull         ResponseGroup = "OfferFull"
+	ResponseGroupOfferListings     ResponseGroup = "OfferListings"
+	ResponseGroupOffers            ResponseGroup = "Offers"
+	ResponseGroupOfferSummary      ResponseGroup = "OfferSummary"
+	ResponseGroupPromotionSummary  ResponseGroup = "PromotionSummary"
+	ResponseGroupRelatedItems      ResponseGroup = "RelatedItems"
+	ResponseGroupRequest           ResponseGroup = "Request"
+	ResponseGroupReviews           ResponseGroup = "Reviews"
+	ResponseGroupSalesRank         ResponseGroup = "SalesRank"
+	ResponseGroupSearchBins        ResponseGroup = "SearchBins"
+	ResponseGroupSimilarities      ResponseGroup = "Similarities"
+	ResponseGroupSmall             ResponseGroup = "Small"
+	ResponseGroupTopSellers        ResponseGroup = "TopSellers"
+	ResponseGroupTracks            ResponseGroup = "Tracks"
+	ResponseGroupVariationImages   ResponseGroup = "VariationImages"
+	ResponseGroupVariationMatrix   ResponseGroup = "VariationMatrix"
+	ResponseGroupVariationOffers   ResponseGroup = "VariationOffers"
+	ResponseGroupVariations        ResponseGroup = "Variations"
+	ResponseGroupVariationSummary  ResponseGroup = "VariationSummary"
+)
+
+// Bool returns a pointer to v, to set the optional boolean query fields
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int returns a pointer to v, to set the optional integer query fields
+// whose zero value is meaningful, like TruncateReviewsAt
+func Int(v int) *int {
+	return &v
+}
+
+// formatBool converts an optional boolean to the wire format
+func formatBool(v *bool) string {
+	switch {
+	case v == nil:
+		return ""
+	case *v:
+		return "True"
+	default:
+		return "False"
+	}
+}
+
+// formatInt converts an optional integer to the wire format, zero meaning
+// that the parameter is not set
+func formatInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(v)
+}
+
+// formatOptionalInt converts an optional integer to the wire format
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*v)
+}
+
+// joinResponseGroups converts a list of response groups to the wire format
+func joinResponseGroups(groups []ResponseGroup) string {
+	names := make([]string, len(groups))
+	for i, group := range groups {
+		names[i] = string(group)
+	}
+
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,235 @@
+package amazonpa
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SearchIndexRules describes the ItemSearch parameters accepted by a
+// search index
+type SearchIndexRules struct {
+	// Parameters accepted besides the ones common to all indexes
+	Parameters []string
+	// Sorts are the accepted Sort values, none if sorting is not allowed
+	Sorts []Sort
+	// MaxItemPage is the last result page that can be requested
+	MaxItemPage int
+}
+
+// commonSearchParameters are accepted by every search index
+var commonSearchParameters = map[string]bool{
+	"Availability":          true,
+	"Condition":             true,
+	"IncludeReviewsSummary": true,
+	"ItemPage":              true,
+	"Keywords":              true,
+	"MerchantId":            true,
+	"RelatedItemPage":       true,
+	"RelationshipType":      true,
+	"ResponseGroup":         true,
+	"SearchIndex":           true,
+	"TruncateReviewsAt":     true,
+	"VariationPage":         true,
+}
+
+// searchCriteria are the parameters that select the searched items, at
+// least one of which is required
+var searchCriteria = []string{
+	"Actor", "Artist", "AudienceRating", "Author", "Brand", "BrowseNode",
+	"Composer", "Conductor", "Director", "Keywords", "Manufacturer",
+	"Orchestra", "Power", "Publisher", "Title",
+}
+
+var productSorts = []Sort{
+	"relevancerank", "salesrank", "reviewrank", "price", "-price",
+	"titlerank", "-titlerank", "popularity-rank", "launch-date", "-launch-date",
+}
+
+var (
+	// The All and Blended indexes only accept keywords and cannot be sorted
+	allIndexRules = SearchIndexRules{
+		MaxItemPage: 5,
+	}
+
+	productIndexRules = SearchIndexRules{
+		Parameters:  []string{"Brand", "BrowseNode", "Manufacturer", "MaximumPrice", "MinimumPrice", "MinPercentageOff", "Title"},
+		Sorts:       productSorts,
+		MaxItemPage: 10,
+	}
+
+	booksIndexRules = SearchIndexRules{
+		Parameters:  []string{"Author", "BrowseNode", "MaximumPrice", "MinimumPrice", "MinPercentageOff", "Power", "Publisher", "Title"},
+		Sorts:       []Sort{"relevancerank", "salesrank", "reviewrank", "pricerank", "inverse-pricerank", "price", "-price", "daterank", "titlerank", "-titlerank", "-unit-sales", "-publication_date"},
+		MaxItemPage: 10,
+	}
+
+	musicIndexRules = SearchIndexRules{
+		Parameters:  []string{"Artist", "BrowseNode", "Composer", "Conductor", "MaximumPrice", "MinimumPrice", "MinPercentageOff", "Orchestra", "Title"},
+		Sorts:       []Sort{"relevancerank", "salesrank", "psrank", "price", "-price", "titlerank", "-titlerank", "artistrank", "orig-rel-date", "release-date", "releasedate", "-releasedate"},
+		MaxItemPage: 10,
+	}
+
+	videoIndexRules = SearchIndexRules{
+		Parameters:  []string{"Actor", "AudienceRating", "BrowseNode", "Director", "MaximumPrice", "MinimumPrice", "MinPercentageOff", "Publisher", "Title"},
+		Sorts:       []Sort{"relevancerank", "salesrank", "price", "-price", "titlerank", "-titlerank", "-video-release-date", "-releasedate"},
+		MaxItemPage: 10,
+	}
+)
+
+// searchIndexRules are the rules of each search index name
+var searchIndexRules = map[SearchIndex]SearchIndexRules{
+	"All":          allIndexRules,
+	"Blended":      allIndexRules,
+	"Books":        booksIndexRules,
+	"ForeignBooks": booksIndexRules,
+	"KindleStore":  booksIndexRules,
+	"Magazines":    booksIndexRules,
+	"Classical":    musicIndexRules,
+	"DigitalMusic": musicIndexRules,
+	"MP3Downloads": musicIndexRules,
+	"Music":        musicIndexRules,
+	"DVD":          videoIndexRules,
+	"Movies":       videoIndexRules,
+	"UnboxVideo":   videoIndexRules,
+	"Video":        videoIndexRules,
+}
+
+// marketplace returns the rules of the given search indexes, using the
+// generic product rules for the indexes without specific rules
+func marketplace(indexes ...SearchIndex) map[SearchIndex]SearchIndexRules {
+	rules := map[SearchIndex]SearchIndexRules{}
+
+	for _, index := range indexes {
+		if specific, ok := searchIndexRules[index]; ok {
+			rules[index] = specific
+		} else {
+			rules[index] = productIndexRules
+		}
+	}
+
+	return rules
+}
+
+var europeanIndexes = []SearchIndex{
+	"All", "Apparel", "Automotive", "Baby", "Beauty", "Blended", "Books",
+	"Classical", "DVD", "Electronics", "ForeignBooks", "GiftCards", "Grocery",
+	"Handmade", "HealthPersonalCare", "HomeGarden", "Industrial", "Jewelry",
+	"KindleStore", "Kitchen", "Lighting", "Luggage", "Magazines", "MobileApps",
+	"MP3Downloads", "Music", "MusicalInstruments", "OfficeProducts", "Pantry",
+	"PCHardware", "PetSupplies", "Photo", "Shoes", "Software", "SportingGoods",
+	"Tools", "Toys", "UnboxVideo", "VideoGames", "Watches",
+}
+
+// SearchIndexes lists the search indexes available in each marketplace
+// region, with the parameters they accept. The tables cover the indexes
+// documented for the 2013-08-01 API version; entries can be added or
+// changed to follow the API. Queries for regions missing from the tables
+// are not validated.
+var SearchIndexes = map[string]map[SearchIndex]SearchIndexRules{
+	"BR": marketplace("All", "Books", "KindleStore", "MobileApps"),
+	"CA": marketplace("All", "Apparel", "Automotive", "Baby", "Beauty", "Blended", "Books", "DVD", "Electronics", "GiftCards", "Grocery", "HealthPersonalCare", "Industrial", "Jewelry", "KindleStore", "Kitchen", "LawnAndGarden", "Luggage", "MobileApps", "Music", "MusicalInstruments", "OfficeProducts", "PetSupplies", "Shoes", "Software", "SportingGoods", "Tools", "Toys", "VideoGames", "Watches"),
+	"CN": marketplace("All", "Apparel", "Appliances", "Automotive", "Baby", "Beauty", "Books", "Electronics", "Grocery", "HealthPersonalCare", "Home", "HomeImprovement", "Jewelry", "KindleStore", "Miscellaneous", "MobileApps", "Music", "MusicalInstruments", "OfficeProducts", "PCHardware", "PetSupplies", "Photo", "Shoes", "Software", "SportingGoods", "Toys", "Video", "VideoGames", "Watches"),
+	"DE": marketplace(europeanIndexes...),
+	"ES": marketplace(europeanIndexes...),
+	"FR": marketplace(europeanIndexes...),
+	"IN": marketplace("All", "Apparel", "Appliances", "Automotive", "Baby", "Beauty", "Books", "DVD", "Electronics", "Furniture", "GiftCards", "Grocery", "HealthPersonalCare", "HomeGarden", "Industrial", "Jewelry", "KindleStore", "LawnAndGarden", "Luggage", "LuxuryBeauty", "Music", "MusicalInstruments", "OfficeProducts", "PCHardware", "PetSupplies", "Shoes", "Software", "SportingGoods", "Toys", "VideoGames", "Watches"),
+	"IT": marketplace(europeanIndexes...),
+	"JP": marketplace("All", "Apparel", "Appliances", "Automotive", "Baby", "Beauty", "Blended", "Books", "Classical", "CreditCards", "DVD", "Electronics", "ForeignBooks", "GiftCards", "Grocery", "HealthPersonalCare", "Hobbies", "HomeImprovement", "Industrial", "Jewelry", "KindleStore", "Kitchen", "MobileApps", "MP3Downloads", "Music", "MusicalInstruments", "OfficeProducts", "PCHardware", "PetSupplies", "Shoes", "Software", "SportingGoods", "Toys", "Video", "VideoGames", "Watches"),
+	"MX": marketplace("All", "Baby", "Books", "DVD", "Electronics", "HealthPersonalCare", "HomeImprovement", "KindleStore", "Kitchen", "Music", "OfficeProducts", "Software", "SportingGoods", "VideoGames", "Watches"),
+	"UK": marketplace(europeanIndexes...),
+	"US": marketplace("All", "Appliances", "ArtsAndCrafts", "Automotive", "Baby", "Beauty", "Blended", "Books", "Collectibles", "Electronics", "Fashion", "FashionBaby", "FashionBoys", "FashionGirls", "FashionMen", "FashionWomen", "GiftCards", "Grocery", "Handmade", "HealthPersonalCare", "HomeGarden", "Industrial", "KindleStore", "LawnAndGarden", "Luggage", "Magazines", "Merchants", "MobileApps", "Movies", "MP3Downloads", "Music", "MusicalInstruments", "OfficeProducts", "PCHardware", "PetSupplies", "Software", "SportingGoods", "Tools", "Toys", "UnboxVideo", "Vehicles", "VideoGames", "Wine", "Wireless"),
+}
+
+// ValidationError describes a query that would be rejected by the API,
+// detected before sending the request
+type ValidationError struct {
+	Region      string
+	SearchIndex SearchIndex
+	Parameter   string
+	Reason      string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Parameter == "" {
+		return fmt.Sprintf("amazonpa: invalid ItemSearch query in %s: %s", e.Region, e.Reason)
+	}
+
+	return fmt.Sprintf("amazonpa: invalid ItemSearch query in %s: %s %s", e.Region, e.Parameter, e.Reason)
+}
+
+// Validate checks the query against the rules of its search index in the
+// marketplace of the given region
+func (query ItemSearchQuery) Validate(region string) error {
+	indexes, ok := SearchIndexes[region]
+	if !ok {
+		return nil
+	}
+
+	invalid := func(parameter, reason string, args ...interface{}) error {
+		return &ValidationError{region, query.SearchIndex, parameter, fmt.Sprintf(reason, args...)}
+	}
+
+	if query.SearchIndex == "" {
+		return invalid("SearchIndex", "is required")
+	}
+
+	rules, ok := indexes[query.SearchIndex]
+	if !ok {
+		return invalid("SearchIndex", "%q is not available in this marketplace", query.SearchIndex)
+	}
+
+	parameters := query.Parameters()
+
+	allowed := map[string]bool{}
+	for _, parameter := range rules.Parameters {
+		allowed[parameter] = true
+	}
+
+	// Check the parameters in a stable order
+	var names []string
+	for name, value := range parameters {
+		if value != "" && name != "Sort" && !commonSearchParameters[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !allowed[name] {
+			return invalid(name, "is not accepted by the %s search index", query.SearchIndex)
+		}
+	}
+
+	if query.Sort != "" {
+		if len(rules.Sorts) == 0 {
+			return invalid("Sort", "is not accepted by the %s search index", query.SearchIndex)
+		}
+		if !containsSort(rules.Sorts, query.Sort) {
+			return invalid("Sort", "%q is not a valid sort for the %s search index", query.Sort, query.SearchIndex)
+		}
+	}
+
+	if query.ItemPage != 0 {
+		if query.ItemPage < 1 || query.ItemPage > rules.MaxItemPage {
+			return invalid("ItemPage", "must be between 1 and %d for the %s search index", rules.MaxItemPage, query.SearchIndex)
+		}
+	}
+
+	for _, criterion := range searchCriteria {
+		if parameters[criterion] != "" {
+			return nil
+		}
+	}
+
+	return invalid("", "at least one search parameter (such as Keywords) is required")
+}
+
+func containsSort(sorts []Sort, value Sort) bool {
+	for _, sort := range sorts {
+		if sort == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package amazonpa
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateItemSearchQuery(t *testing.T) {
+	valid := []ItemSearchQuery{
+		{SearchIndex: "All", Keywords: "mouse", ItemPage: 5},
+		{SearchIndex: "Books", Author: "Pike", Sort: "salesrank", MinimumPrice: 1000},
+		{SearchIndex: "Music", Artist: "Miles Davis", Sort: "-releasedate"},
+		{SearchIndex: "Electronics", Brand: "Logitech", ItemPage: 10},
+	}
+
+	for _, query := range valid {
+		if err := query.Validate("US"); err != nil {
+			t.Errorf("Query %+v must be valid: %v", query, err)
+		}
+	}
+
+	invalid := map[string]ItemSearchQuery{
+		"SearchIndex": {Keywords: "mouse"},
+		"Sort":        {SearchIndex: "All", Keywords: "mouse", Sort: "salesrank"},
+		"Author":      {SearchIndex: "Electronics", Author: "Pike"},
+		"ItemPage":    {SearchIndex: "All", Keywords: "mouse", ItemPage: 6},
+		"":            {SearchIndex: "Books", Sort: "salesrank"},
+	}
+
+	for parameter, query := range invalid {
+		err := query.Validate("US")
+
+		var validationError *ValidationError
+		if !errors.As(err, &validationError) {
+			t.Errorf("Query %+v must be invalid", query)
+			continue
+		}
+		assertEqualStr(t, validationError.Parameter, parameter, "Bad invalid parameter for", validationError.Error())
+	}
+
+	err := ItemSearchQuery{SearchIndex: "Books", Sort: "bestselling", Keywords: "go"}.Validate("US")
+	assertEqualStr(t, err.Error(), `amazonpa: invalid ItemSearch query in US: Sort "bestselling" is not a valid sort for the Books search index`, "Bad error message")
+
+	if err := (ItemSearchQuery{SearchIndex: "Wine", Keywords: "barolo"}).Validate("IT"); err == nil {
+		t.Error("Search indexes missing from the marketplace must be rejected")
+	}
+}
+
+func TestItemSearchValidatesBeforeRequest(t *testing.T) {
+	client := newTestClient()
+	client.SetHTTPClient(nil)
+
+	_, err := client.ItemSearch(ItemSearchQuery{SearchIndex: "All", Keywords: "mouse", Sort: "price"})
+
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) {
+		t.Errorf("Expected a ValidationError, got %v", err)
+	}
+}